@@ -42,11 +42,15 @@ func (g *Gen) Run(args []string) error {
 	}
 
 	// Parse mappers
-	spec, err := parse.Read(cfg.BaseFilename)
+	spec, err := parse.Read(cfg.BaseFilename, arguments.MapperOptions)
 	if err != nil {
 		return fmt.Errorf("could not parse file %s: %w", cfg.BaseFilename, err)
 	}
 
+	for _, warning := range spec.Warnings {
+		fmt.Fprintf(os.Stderr, "WARNING: %s\n", warning)
+	}
+
 	if len(spec.Mappers) == 0 {
 		// Nothing to do.
 		return nil
@@ -65,7 +69,8 @@ func (g *Gen) Run(args []string) error {
 }
 
 type arguments struct {
-	OutputFile string
+	OutputFile    string
+	MapperOptions parse.MapperOptions
 }
 
 var ErrInvalidArgs = errors.New("invalid args")
@@ -73,6 +78,14 @@ var ErrInvalidArgs = errors.New("invalid args")
 func (g *Gen) parseArgs(args []string) (arguments, error) {
 	fs := flag.NewFlagSet("gen", flag.ContinueOnError)
 	outputFile := fs.String("o", "", "output file")
+	unmapped := fs.String("unmapped", string(parse.PolicyError),
+		"policy for unmapped target fields: error, warn or ignore")
+	unmappedSource := fs.String("unmappedsource", string(parse.PolicyIgnore),
+		"policy for unused source fields: error, warn or ignore")
+	match := fs.String("match", string(parse.MatchExact),
+		"how field names are matched for implicit mapping: exact, caseinsensitive or normalized")
+	nullValues := fs.String("nullvalues", string(parse.NullValuesSet),
+		"what update methods do with nil or zero sources: set or skip")
 
 	if err := fs.Parse(args); err != nil {
 		fs.Usage()
@@ -83,8 +96,36 @@ func (g *Gen) parseArgs(args []string) (arguments, error) {
 		return arguments{}, ErrInvalidArgs
 	}
 
+	unmappedPolicy, err := parse.ParsePolicy(*unmapped)
+	if err != nil {
+		return arguments{}, fmt.Errorf("invalid -unmapped: %w", err)
+	}
+
+	unmappedSourcePolicy, err := parse.ParsePolicy(*unmappedSource)
+	if err != nil {
+		return arguments{}, fmt.Errorf("invalid -unmappedsource: %w", err)
+	}
+
+	nameMatching, err := parse.ParseNameMatching(*match)
+	if err != nil {
+		return arguments{}, fmt.Errorf("invalid -match: %w", err)
+	}
+
+	nullValuesStrategy, err := parse.ParseNullValues(*nullValues)
+	if err != nil {
+		return arguments{}, fmt.Errorf("invalid -nullvalues: %w", err)
+	}
+
 	return arguments{
 		OutputFile: *outputFile,
+		MapperOptions: parse.MapperOptions{
+			UnmappedTargets: unmappedPolicy,
+			UnmappedSources: unmappedSourcePolicy,
+			SourceTag:       "",
+			TargetTag:       "",
+			NameMatching:    nameMatching,
+			NullValues:      nullValuesStrategy,
+		},
 	}, nil
 }
 
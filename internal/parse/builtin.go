@@ -0,0 +1,249 @@
+package parse
+
+import (
+	"fmt"
+	"go/types"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Packages which generated code refers to without them being imported by
+// the mapper's package.
+//
+//nolint:gochecknoglobals
+var generatedImports = []string{"fmt", "strconv", "time"}
+
+// PackageNames returns the names of packages which code generated in pkg may
+// refer to, and so which must not be shadowed. pkg may be nil.
+func PackageNames(pkg *types.Package) []string {
+	result := append([]string(nil), generatedImports...)
+
+	if pkg != nil {
+		for _, imported := range pkg.Imports() {
+			result = append(result, imported.Name())
+		}
+	}
+
+	return result
+}
+
+// Formats for converting between time.Time and strings, by name.
+//
+//nolint:gochecknoglobals
+var timeFormats = map[string]string{
+	"ansic":       "time.ANSIC",
+	"kitchen":     "time.Kitchen",
+	"rfc1123":     "time.RFC1123",
+	"rfc1123z":    "time.RFC1123Z",
+	"rfc3339":     "time.RFC3339",
+	"rfc3339nano": "time.RFC3339Nano",
+	"rfc822":      "time.RFC822",
+	"rfc822z":     "time.RFC822Z",
+	"rfc850":      "time.RFC850",
+}
+
+// Units for converting between time.Duration and integers, by name.
+//
+//nolint:gochecknoglobals
+var durationUnits = map[string]string{
+	"nanoseconds":  "time.Nanosecond",
+	"microseconds": "time.Microsecond",
+	"milliseconds": "time.Millisecond",
+	"seconds":      "time.Second",
+	"minutes":      "time.Minute",
+	"hours":        "time.Hour",
+}
+
+// Look for a built-in converter between the types, such as time.Time to an
+// RFC3339 string.
+func (c *typeChecker) resolveBuiltinConversion(
+	fn Function,
+	from, to types.Type,
+	opts conversionOptions,
+) (Conversion, bool, error) {
+	builtin := BuiltinConversion{
+		Builtin:      "",
+		From:         from,
+		To:           to,
+		Format:       "",
+		ReturnsError: false,
+	}
+
+	var err error
+
+	switch {
+	case isTime(from) && isString(to):
+		builtin.Builtin = BuiltinFormatTime
+		builtin.Format, err = timeFormat(opts.format)
+	case isString(from) && isTime(to):
+		builtin.Builtin = BuiltinParseTime
+		builtin.Format, err = timeFormat(opts.format)
+		builtin.ReturnsError = true
+	case isDuration(from) && isIntegerType(to) && !isDuration(to):
+		builtin.Builtin = BuiltinDurationToInt
+		builtin.Format, err = durationUnit(opts.format)
+	case isIntegerType(from) && !isDuration(from) && isDuration(to):
+		builtin.Builtin = BuiltinIntToDuration
+		builtin.Format, err = durationUnit(opts.format)
+	case isBytes(from) && isString(to), isString(from) && isBytes(to):
+		return TypeConversion{
+			Type: to,
+		}, true, nil
+	case isIntegerType(from) && !isDuration(from) && isString(to):
+		builtin.Builtin = BuiltinFormatInt
+	case isString(from) && isIntegerType(to) && !isDuration(to):
+		builtin.Builtin = BuiltinParseInt
+		builtin.ReturnsError = true
+	case isPointer(from) && !isPointer(to):
+		return c.resolveDerefConversion(fn, from, to, opts)
+	case !isPointer(from) && isPointer(to):
+		return c.resolveAddressConversion(fn, from, to, opts)
+	default:
+		return nil, false, nil
+	}
+
+	if err != nil {
+		return nil, true, err
+	}
+
+	if builtin.ReturnsError && !fn.ReturnsError {
+		return nil, true, fmt.Errorf("converting %s to %s can fail, so %s must return an error: %w",
+			c.typeString(from), c.typeString(to), fn.Name, ErrSpec)
+	}
+
+	return builtin, true, nil
+}
+
+// Pointers are dereferenced (if not nil), and then converted.
+func (c *typeChecker) resolveDerefConversion(
+	fn Function,
+	from, to types.Type,
+	opts conversionOptions,
+) (Conversion, bool, error) {
+	elem, err := c.resolveConversion(fn, deref(from), to, opts)
+	if err != nil {
+		return nil, true, err
+	}
+
+	return DerefConversion{
+		Type: to,
+		Elem: elem,
+	}, true, nil
+}
+
+// Values are converted, and then their address taken.
+func (c *typeChecker) resolveAddressConversion(
+	fn Function,
+	from, to types.Type,
+	opts conversionOptions,
+) (Conversion, bool, error) {
+	elem, err := c.resolveConversion(fn, from, deref(to), opts)
+	if err != nil {
+		return nil, true, err
+	}
+
+	return AddressConversion{
+		Elem: elem,
+	}, true, nil
+}
+
+// Whether the format option is used by the conversion.
+func usesFormat(conversion Conversion) bool {
+	switch v := conversion.(type) {
+	case BuiltinConversion:
+		return v.Format != ""
+	case SliceConversion:
+		return usesFormat(v.Elem)
+	case MapConversion:
+		return usesFormat(v.Key) || usesFormat(v.Elem)
+	case DerefConversion:
+		return usesFormat(v.Elem)
+	case AddressConversion:
+		return usesFormat(v.Elem)
+	}
+
+	return false
+}
+
+// Formats are given by name, or as a quoted layout.
+func timeFormat(format string) (string, error) {
+	if format == "" {
+		return timeFormats["rfc3339"], nil
+	}
+
+	if layout, ok := timeFormats[strings.ToLower(format)]; ok {
+		return layout, nil
+	}
+
+	if _, err := strconv.Unquote(format); err == nil {
+		return format, nil
+	}
+
+	return "", fmt.Errorf("[%s] is not a valid time format (expected one of %s, or a quoted layout): %w",
+		format, joinKeys(timeFormats), ErrSpec)
+}
+
+func durationUnit(format string) (string, error) {
+	if format == "" {
+		return durationUnits["seconds"], nil
+	}
+
+	if unit, ok := durationUnits[strings.ToLower(format)]; ok {
+		return unit, nil
+	}
+
+	return "", fmt.Errorf("[%s] is not a valid duration unit (expected one of %s): %w",
+		format, joinKeys(durationUnits), ErrSpec)
+}
+
+func joinKeys(m map[string]string) string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return strings.Join(keys, ", ")
+}
+
+func isTime(typ types.Type) bool {
+	return isNamed(typ, "time", "Time")
+}
+
+func isDuration(typ types.Type) bool {
+	return isNamed(typ, "time", "Duration")
+}
+
+func isNamed(typ types.Type, pkgPath string, name string) bool {
+	named, ok := typ.(*types.Named)
+	if !ok {
+		return false
+	}
+
+	obj := named.Obj()
+
+	return obj.Pkg() != nil && obj.Pkg().Path() == pkgPath && obj.Name() == name
+}
+
+func isString(typ types.Type) bool {
+	basic, ok := typ.Underlying().(*types.Basic)
+	return ok && basic.Info()&types.IsString != 0
+}
+
+func isIntegerType(typ types.Type) bool {
+	basic, ok := typ.Underlying().(*types.Basic)
+	return ok && isInteger(basic)
+}
+
+func isBytes(typ types.Type) bool {
+	slice, ok := typ.Underlying().(*types.Slice)
+	if !ok {
+		return false
+	}
+
+	basic, ok := slice.Elem().Underlying().(*types.Basic)
+
+	return ok && basic.Kind() == types.Byte
+}
@@ -0,0 +1,585 @@
+package parse
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+// typeChecker resolves and validates the parts of a mapper against the
+// types declared in its package.
+type typeChecker struct {
+	fset     *token.FileSet
+	pkg      *types.Package
+	importer types.Importer
+	mapper   *types.Interface
+	// A position within the mapper file, for checking expressions in the
+	// scope of its imports.
+	scope token.Pos
+}
+
+func newTypeChecker(loaded loadedPackage, mapperName string) (*typeChecker, error) {
+	pkg := loaded.types
+
+	obj := pkg.Scope().Lookup(mapperName)
+	if obj == nil {
+		return nil, fmt.Errorf("could not resolve type of mapper %s: %w", mapperName, ErrSpec)
+	}
+
+	mapper, ok := obj.Type().Underlying().(*types.Interface)
+	if !ok {
+		return nil, fmt.Errorf("mapper %s is not an interface: %w", mapperName, ErrSpec)
+	}
+
+	return &typeChecker{
+		fset:     loaded.fset,
+		pkg:      pkg,
+		importer: loaded.importer,
+		mapper:   mapper,
+		scope:    loaded.file.Pos(),
+	}, nil
+}
+
+func (c *typeChecker) resolveFunction(fn Function) (Function, error) {
+	method := c.lookupMethod(fn.Name)
+	if method == nil {
+		return Function{}, fmt.Errorf("could not resolve method %s: %w", fn.Name, ErrSpec)
+	}
+
+	sig, _ := method.Type().(*types.Signature)
+
+	// Resolve parameters...
+	params := make([]Parameter, len(fn.Parameters))
+
+	for i, param := range fn.Parameters {
+		resolved := sig.Params().At(i).Type()
+		if !isValidType(resolved) {
+			return Function{}, fmt.Errorf("could not resolve type %s of parameter %s of %s: %w",
+				param.Type, param.Name, fn.Name, ErrSpec)
+		}
+
+		param.ResolvedType = resolved
+		params[i] = param
+	}
+
+	fn.Parameters = params
+
+	if fn.Result == "" {
+		return c.resolveUpdateFunction(fn, sig)
+	}
+
+	// ...and result.
+	result := sig.Results().At(0).Type()
+	if !isValidType(result) {
+		return Function{}, fmt.Errorf("could not resolve result type %s of %s: %w",
+			fn.Result, fn.Name, ErrSpec)
+	}
+
+	if fn.ReturnsError && !returnsError(sig) {
+		return Function{}, fmt.Errorf("second result of %s must be the error type: %w",
+			fn.Name, ErrSpec)
+	}
+
+	fn.ResolvedResult = result
+
+	return fn, nil
+}
+
+// Functions without a result update their last parameter in place, which
+// must be a pointer to a struct.
+func (c *typeChecker) resolveUpdateFunction(fn Function, sig *types.Signature) (Function, error) {
+	if fn.ReturnsError && !types.Identical(sig.Results().At(0).Type(), errorType()) {
+		return Function{}, fmt.Errorf("the result of %s must be the error type: %w", fn.Name, ErrSpec)
+	}
+
+	if len(fn.Parameters) == 0 {
+		return Function{}, fmt.Errorf("%s has no result, so must update its last parameter: %w", fn.Name, ErrSpec)
+	}
+
+	target := fn.Parameters[len(fn.Parameters)-1]
+	if !isPointer(target.ResolvedType) || !isStruct(target.ResolvedType) {
+		return Function{}, fmt.Errorf("%s has no result, so its last parameter %s must be a pointer to a struct: %w",
+			fn.Name, target.Name, ErrSpec)
+	}
+
+	fn.ResolvedResult = target.ResolvedType
+	fn.UpdateTarget = target.Name
+
+	return fn, nil
+}
+
+// The generated struct mustn't clash with anything else in the package,
+// except for the output of a previous run (an empty struct in another file),
+// which will be overwritten.
+func (c *typeChecker) checkImplName(name string) error {
+	obj := c.pkg.Scope().Lookup(name)
+	if obj == nil {
+		return nil
+	}
+
+	fileScope := c.pkg.Scope().Innermost(c.scope)
+	structType, isStruct := obj.Type().Underlying().(*types.Struct)
+	_, isType := obj.(*types.TypeName)
+
+	if isType && isStruct && structType.NumFields() == 0 && fileScope != nil && !fileScope.Contains(obj.Pos()) {
+		return nil
+	}
+
+	return fmt.Errorf("%s is already declared in the package, please set another name with "+
+		"`+juryrig:impl:<name>`: %w", name, ErrSpec)
+}
+
+// The receiver mustn't shadow the packages or package identifiers which the
+// generated code may refer to.
+func (c *typeChecker) checkReceiverName(name string) error {
+	if c.isPackageName(name) {
+		return fmt.Errorf("receiver %s has the same name as a package which generated code may use: %w",
+			name, ErrSpec)
+	}
+
+	if c.pkg.Scope().Lookup(name) != nil {
+		return fmt.Errorf("receiver %s has the same name as an identifier of the package: %w", name, ErrSpec)
+	}
+
+	return nil
+}
+
+func (c *typeChecker) isPackageName(name string) bool {
+	for _, pkgName := range PackageNames(c.pkg) {
+		if pkgName == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *typeChecker) checkFunctionOptions(fn Function, options FunctionOptions) error {
+	if options.ReturnNil {
+		if fn.UpdateTarget != "" {
+			return fmt.Errorf("returnnil requires a result: %w", ErrSpec)
+		}
+
+		if !isPointer(fn.ResolvedResult) {
+			return fmt.Errorf("returnnil requires a pointer result: %w", ErrSpec)
+		}
+
+		if len(pointerParameters(fn)) == 0 {
+			return fmt.Errorf("returnnil requires at least one pointer parameter: %w", ErrSpec)
+		}
+	}
+
+	if options.InverseOf == fn.Name {
+		return fmt.Errorf("%s cannot be the inverse of itself: %w", fn.Name, ErrSpec)
+	}
+
+	if options.Inherit == fn.Name {
+		return fmt.Errorf("%s cannot inherit from itself: %w", fn.Name, ErrSpec)
+	}
+
+	if options.NullValues != "" && fn.UpdateTarget == "" {
+		return fmt.Errorf("nullvalues only applies to methods which update a parameter: %w", ErrSpec)
+	}
+
+	return nil
+}
+
+// Check the directive is valid for fn, and fill in any details which
+// depend on types.
+func (c *typeChecker) resolveDirective(fn Function, directive Directive) (Directive, error) {
+	switch v := directive.(type) {
+	case LinkDirective:
+		return c.resolveLinkDirective(fn, v)
+	case LinkFuncDirective:
+		return c.resolveLinkFuncDirective(fn, v)
+	case ConstDirective:
+		return c.resolveConstDirective(fn, v)
+	case ExprDirective:
+		return c.resolveExprDirective(fn, v)
+	case IgnoreDirective:
+		_, err := c.targetType(fn, v.Target)
+		return v, err
+	}
+
+	return directive, nil
+}
+
+func (c *typeChecker) resolveLinkDirective(fn Function, directive LinkDirective) (Directive, error) {
+	source, from, err := c.resolveSource(fn, directive.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	directive.Source = source
+
+	to, err := c.targetType(fn, directive.Target)
+	if err != nil {
+		return nil, err
+	}
+
+	directive.Conversion, err = c.resolveConversion(fn, from, to, conversionOptions{
+		narrowing: directive.AllowNarrowing,
+		format:    directive.Format,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot link %s to %s: %w",
+			formatSource(directive.Source), directive.Target.Field, err)
+	}
+
+	if directive.Format != "" && !usesFormat(directive.Conversion) {
+		return nil, fmt.Errorf("cannot link %s to %s: the format option only applies to times and durations: %w",
+			formatSource(directive.Source), directive.Target.Field, ErrSpec)
+	}
+
+	if directive.Default != "" {
+		return c.resolveLinkDefault(fn, directive)
+	}
+
+	return directive, nil
+}
+
+func (c *typeChecker) resolveLinkDefault(fn Function, directive LinkDirective) (Directive, error) {
+	// The source must be comparable with its zero value...
+	if !types.Comparable(directive.Source.Type) && !isNillable(directive.Source.Type) {
+		return nil, fmt.Errorf("source %s of type %s cannot be compared with its zero value, so cannot have a default: %w",
+			formatSource(directive.Source), c.typeString(directive.Source.Type), ErrSpec)
+	}
+
+	// ...and the default must be a constant which suits the target.
+	checked, err := c.checkExpr(fn, directive.Target, directive.Default)
+	if err != nil {
+		return nil, fmt.Errorf("invalid default: %w", err)
+	}
+
+	if checked.typeAndValue.Value == nil {
+		return nil, fmt.Errorf("default [%s] is not a constant: %w", directive.Default, ErrSpec)
+	}
+
+	directive.DefaultImports = checked.imports
+
+	return directive, nil
+}
+
+func (c *typeChecker) resolveConstDirective(fn Function, directive ConstDirective) (Directive, error) {
+	if _, err := c.targetType(fn, directive.Target); err != nil {
+		return nil, err
+	}
+
+	checked, err := c.checkExpr(fn, directive.Target, directive.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	if checked.typeAndValue.Value == nil {
+		return nil, fmt.Errorf("[%s] is not a constant: %w", directive.Value, ErrSpec)
+	}
+
+	directive.Imports = checked.imports
+
+	return directive, nil
+}
+
+func (c *typeChecker) resolveExprDirective(fn Function, directive ExprDirective) (Directive, error) {
+	if _, err := c.targetType(fn, directive.Target); err != nil {
+		return nil, err
+	}
+
+	checked, err := c.checkExpr(fn, directive.Target, directive.Expression)
+	if err != nil {
+		return nil, err
+	}
+
+	directive.Imports = checked.imports
+	directive.Sources = checked.sources
+
+	return directive, nil
+}
+
+func (c *typeChecker) resolveLinkFuncDirective(fn Function, directive LinkFuncDirective) (Directive, error) {
+	sources := make([]Source, len(directive.Sources))
+
+	for i, source := range directive.Sources {
+		resolved, _, err := c.resolveSource(fn, source)
+		if err != nil {
+			return nil, err
+		}
+
+		sources[i] = resolved
+	}
+
+	directive.Sources = sources
+
+	if _, err := c.targetType(fn, directive.Target); err != nil {
+		return nil, err
+	}
+
+	method := c.lookupMethod(directive.FunctionName)
+	if method == nil {
+		return nil, fmt.Errorf("linked function %s is not a method of the mapper: %w",
+			directive.FunctionName, ErrSpec)
+	}
+
+	if directive.Inverse != "" && c.lookupMethod(directive.Inverse) == nil {
+		return nil, fmt.Errorf("inverse function %s is not a method of the mapper: %w",
+			directive.Inverse, ErrSpec)
+	}
+
+	// Errors can only be passed on by functions which return them.
+	sig, _ := method.Type().(*types.Signature)
+	directive.ReturnsError = returnsError(sig)
+
+	if directive.ReturnsError && !fn.ReturnsError {
+		return nil, fmt.Errorf("linked function %s returns an error, so %s must as well: %w",
+			directive.FunctionName, fn.Name, ErrSpec)
+	}
+
+	return directive, nil
+}
+
+// Check the source exists, fill in its nil checks, and find its type.
+func (c *typeChecker) resolveSource(fn Function, source Source) (Source, types.Type, error) {
+	param, ok := findParameter(fn, source.Parameter)
+	if !ok || param.Name == fn.UpdateTarget {
+		return Source{}, nil, fmt.Errorf("source %s is not a parameter of %s: %w",
+			source.Parameter, fn.Name, ErrSpec)
+	}
+
+	if source.Field == "" {
+		source.Type = param.ResolvedType
+		return source, param.ResolvedType, nil
+	}
+
+	field, nilChecks, err := c.lookupSourcePath(param.ResolvedType, source.Field)
+	if err != nil {
+		return Source{}, nil, fmt.Errorf("invalid source %s: %w", formatSource(source), err)
+	}
+
+	// (relative to the parameter)
+	source.NilChecks = nil
+	for _, nilCheck := range nilChecks {
+		source.NilChecks = append(source.NilChecks, strings.TrimSuffix(param.Name+"."+nilCheck, "."))
+	}
+
+	source.Type = field.Type()
+
+	return source, field.Type(), nil
+}
+
+// Follow a dotted path of fields, (implicitly) dereferencing pointers
+// along the way. The paths to those pointers are returned as well.
+func (c *typeChecker) lookupSourcePath(typ types.Type, path string) (*types.Var, []string, error) {
+	var (
+		field     *types.Var
+		nilChecks []string
+		walked    []string
+	)
+
+	for _, name := range strings.Split(path, ".") {
+		if isPointer(typ) {
+			nilChecks = append(nilChecks, strings.Join(walked, "."))
+		}
+
+		var embedded []*types.Var
+
+		field, embedded = c.lookupSourceField(typ, name)
+		if field == nil {
+			return nil, nil, fmt.Errorf("type %s has no field %s: %w",
+				c.typeString(typ), name, ErrSpec)
+		}
+
+		// Promoted fields are reached through the embedded fields, which
+		// may be pointers too.
+		for _, hop := range embedded {
+			walked = append(walked, hop.Name())
+			if isPointer(hop.Type()) {
+				nilChecks = append(nilChecks, strings.Join(walked, "."))
+			}
+		}
+
+		walked = append(walked[:len(walked)-len(embedded)], name)
+		typ = field.Type()
+	}
+
+	return field, nilChecks, nil
+}
+
+func (c *typeChecker) targetType(fn Function, target Target) (types.Type, error) {
+	field, err := c.lookupTargetPath(fn.ResolvedResult, target.Field)
+	if err != nil {
+		return nil, fmt.Errorf("invalid target %s: %w", target.Field, err)
+	}
+
+	return field.Type(), nil
+}
+
+// Follow a dotted path of fields, each of which (except the last) must be a
+// struct or a pointer to one, so that it can be built inline.
+func (c *typeChecker) lookupTargetPath(typ types.Type, path string) (*types.Var, error) {
+	var field *types.Var
+
+	for _, name := range strings.Split(path, ".") {
+		if _, ok := deref(typ).Underlying().(*types.Struct); !ok {
+			return nil, fmt.Errorf("type %s is not a struct: %w", c.typeString(typ), ErrSpec)
+		}
+
+		field = c.lookupTargetField(typ, name)
+		if field == nil {
+			return nil, fmt.Errorf("type %s has no field %s: %w",
+				c.typeString(typ), name, ErrSpec)
+		}
+
+		typ = field.Type()
+	}
+
+	return field, nil
+}
+
+// Note: result is nillable.
+func (c *typeChecker) lookupMethod(name string) *types.Func {
+	for i := 0; i < c.mapper.NumMethods(); i++ {
+		if method := c.mapper.Method(i); method.Name() == name {
+			return method
+		}
+	}
+
+	return nil
+}
+
+// Sources may refer to promoted fields.
+// Note: result is nillable.
+func (c *typeChecker) lookupSourceField(typ types.Type, name string) (*types.Var, []*types.Var) {
+	obj, index, _ := types.LookupFieldOrMethod(typ, true, c.pkg, name)
+
+	field, ok := obj.(*types.Var)
+	if !ok || !field.IsField() {
+		return nil, nil
+	}
+
+	// The embedded fields which field is promoted through, if any.
+	var embedded []*types.Var
+
+	for _, i := range index[:len(index)-1] {
+		structType, _ := deref(typ).Underlying().(*types.Struct)
+		hop := structType.Field(i)
+		embedded = append(embedded, hop)
+		typ = hop.Type()
+	}
+
+	return field, embedded
+}
+
+// Targets are set with composite literals, and so must be direct fields.
+// Note: result is nillable.
+func (c *typeChecker) lookupTargetField(typ types.Type, name string) *types.Var {
+	for _, field := range c.targetFields(typ) {
+		if field.Name() == name {
+			return field
+		}
+	}
+
+	return nil
+}
+
+// The fields of typ which a composite literal could set.
+func (c *typeChecker) targetFields(typ types.Type) []*types.Var {
+	structType, ok := deref(typ).Underlying().(*types.Struct)
+	if !ok {
+		return nil
+	}
+
+	var result []*types.Var
+
+	for i := 0; i < structType.NumFields(); i++ {
+		if field := structType.Field(i); c.isAccessible(field) {
+			result = append(result, field)
+		}
+	}
+
+	return result
+}
+
+func (c *typeChecker) isAccessible(obj types.Object) bool {
+	return obj.Exported() || obj.Pkg() == c.pkg
+}
+
+func (c *typeChecker) typeString(typ types.Type) string {
+	return types.TypeString(typ, types.RelativeTo(c.pkg))
+}
+
+func findParameter(fn Function, name string) (Parameter, bool) {
+	for _, param := range fn.Parameters {
+		if param.Name == name {
+			return param, true
+		}
+	}
+
+	return Parameter{}, false
+}
+
+func deref(typ types.Type) types.Type {
+	if ptr, ok := typ.Underlying().(*types.Pointer); ok {
+		return ptr.Elem()
+	}
+
+	return typ
+}
+
+func returnsError(sig *types.Signature) bool {
+	results := sig.Results()
+
+	return results.Len() == 2 && types.Identical(results.At(1).Type(), errorType())
+}
+
+func errorType() types.Type {
+	return types.Universe.Lookup("error").Type()
+}
+
+func isStruct(typ types.Type) bool {
+	_, ok := deref(typ).Underlying().(*types.Struct)
+	return ok
+}
+
+// Types which can be compared with nil, but perhaps nothing else.
+func isNillable(typ types.Type) bool {
+	switch typ.Underlying().(type) {
+	case *types.Slice, *types.Map, *types.Signature:
+		return true
+	}
+
+	return false
+}
+
+func isPointer(typ types.Type) bool {
+	_, ok := typ.Underlying().(*types.Pointer)
+	return ok
+}
+
+// The parameters which values are mapped from (i.e. not the update target).
+func sourceParameters(fn Function) []Parameter {
+	var result []Parameter
+
+	for _, param := range fn.Parameters {
+		if param.Name != fn.UpdateTarget {
+			result = append(result, param)
+		}
+	}
+
+	return result
+}
+
+func pointerParameters(fn Function) []Parameter {
+	var result []Parameter
+
+	for _, param := range fn.Parameters {
+		if isPointer(param.ResolvedType) {
+			result = append(result, param)
+		}
+	}
+
+	return result
+}
+
+func isValidType(typ types.Type) bool {
+	return typ != nil && typ != types.Typ[types.Invalid]
+}
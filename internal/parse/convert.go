@@ -0,0 +1,189 @@
+package parse
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+)
+
+// conversionOptions are set by the directive which needs the conversion.
+type conversionOptions struct {
+	// Allow conversions which may lose information, e.g. int64 to int32.
+	narrowing bool
+	// The format for built-in converters, e.g. rfc3339 for times.
+	format string
+}
+
+// Work out how a value of type from can be used as a value of type to in
+// fn. A nil conversion means it can be assigned as is.
+func (c *typeChecker) resolveConversion(fn Function, from, to types.Type, opts conversionOptions) (Conversion, error) {
+	if types.AssignableTo(from, to) {
+		return nil, nil
+	}
+
+	conversion, found, err := c.resolveMethodConversion(fn, from, to)
+	if found || err != nil {
+		return conversion, err
+	}
+
+	return c.resolveValueConversion(fn, from, to, opts)
+}
+
+// Work out how to convert a value without the help of mapper methods.
+func (c *typeChecker) resolveValueConversion(fn Function, from, to types.Type, opts conversionOptions) (Conversion, error) {
+	conversion, found, err := c.resolveBuiltinConversion(fn, from, to, opts)
+	if found || err != nil {
+		return conversion, err
+	}
+
+	conversion, found, err = c.resolveTypeConversion(from, to, opts)
+	if found || err != nil {
+		return conversion, err
+	}
+
+	return c.resolveElemConversion(fn, from, to, opts)
+}
+
+// Go conversions, such as string to a named string type or int32 to int64,
+// are used where they can't lose information (unless narrowing is allowed).
+func (c *typeChecker) resolveTypeConversion(from, to types.Type, opts conversionOptions) (Conversion, bool, error) {
+	// Enum-like types are matched up by their constants, not their values.
+	if c.isEnumPair(from, to) {
+		return nil, true, fmt.Errorf("%s and %s both have constants, so need a mapper method to convert "+
+			"between them (e.g. `To%s(%s) %s`, which is generated as a switch): %w",
+			c.typeString(from), c.typeString(to), typeName(to), c.typeString(from), c.typeString(to), ErrSpec)
+	}
+
+	if types.Identical(from.Underlying(), to.Underlying()) {
+		return TypeConversion{
+			Type: to,
+		}, true, nil
+	}
+
+	fromBasic, fromOk := from.Underlying().(*types.Basic)
+	toBasic, toOk := to.Underlying().(*types.Basic)
+
+	if !fromOk || !toOk || !isNumeric(fromBasic) || !isNumeric(toBasic) || !types.ConvertibleTo(from, to) {
+		return nil, false, nil
+	}
+
+	if !opts.narrowing && !isWidening(fromBasic, toBasic) {
+		return nil, true, fmt.Errorf("converting %s to %s may lose information, add the narrow option to allow it: %w",
+			c.typeString(from), c.typeString(to), ErrSpec)
+	}
+
+	return TypeConversion{
+		Type: to,
+	}, true, nil
+}
+
+// Look for a method of the mapper which does the conversion.
+func (c *typeChecker) resolveMethodConversion(fn Function, from, to types.Type) (Conversion, bool, error) {
+	var candidates []*types.Func
+
+	for i := 0; i < c.mapper.NumMethods(); i++ {
+		method := c.mapper.Method(i)
+		if convertsBetween(method, from, to) {
+			candidates = append(candidates, method)
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return nil, false, nil
+	case 1:
+	default:
+		return nil, true, fmt.Errorf("methods [%s] could all convert %s to %s: %w",
+			joinMethodNames(candidates), c.typeString(from), c.typeString(to), ErrSpec)
+	}
+
+	method := candidates[0]
+	sig, _ := method.Type().(*types.Signature)
+
+	if returnsError(sig) && !fn.ReturnsError {
+		return nil, true, fmt.Errorf("method %s returns an error, so %s must as well: %w",
+			method.Name(), fn.Name, ErrSpec)
+	}
+
+	return MethodConversion{
+		Method:       method.Name(),
+		ReturnsError: returnsError(sig),
+	}, true, nil
+}
+
+// Containers can be converted by converting each of their elements.
+func (c *typeChecker) resolveElemConversion(fn Function, from, to types.Type, opts conversionOptions) (Conversion, error) {
+	fromSlice, fromOk := from.Underlying().(*types.Slice)
+	toSlice, toOk := to.Underlying().(*types.Slice)
+
+	if fromOk && toOk {
+		elem, err := c.resolveConversion(fn, fromSlice.Elem(), toSlice.Elem(), opts)
+		if err != nil {
+			return nil, fmt.Errorf("cannot convert %s to %s: %w",
+				c.typeString(from), c.typeString(to), err)
+		}
+
+		return SliceConversion{
+			Type: to,
+			Elem: elem,
+		}, nil
+	}
+
+	fromMap, fromOk := from.Underlying().(*types.Map)
+	toMap, toOk := to.Underlying().(*types.Map)
+
+	if fromOk && toOk {
+		return c.resolveMapConversion(fn, fromMap, toMap, to, opts)
+	}
+
+	return nil, fmt.Errorf("cannot convert %s to %s: %w",
+		c.typeString(from), c.typeString(to), ErrSpec)
+}
+
+func (c *typeChecker) resolveMapConversion(
+	fn Function,
+	from, to *types.Map,
+	toType types.Type,
+	opts conversionOptions,
+) (Conversion, error) {
+	key, err := c.resolveConversion(fn, from.Key(), to.Key(), opts)
+	if err != nil {
+		return nil, fmt.Errorf("cannot convert %s to %s: %w",
+			c.typeString(from), c.typeString(to), err)
+	}
+
+	elem, err := c.resolveConversion(fn, from.Elem(), to.Elem(), opts)
+	if err != nil {
+		return nil, fmt.Errorf("cannot convert %s to %s: %w",
+			c.typeString(from), c.typeString(to), err)
+	}
+
+	return MapConversion{
+		Type: toType,
+		Key:  key,
+		Elem: elem,
+	}, nil
+}
+
+// Methods which take a single parameter, and produce a single result
+// (optionally with an error), may be used to convert values.
+func convertsBetween(method *types.Func, from, to types.Type) bool {
+	sig, _ := method.Type().(*types.Signature)
+
+	params, results := sig.Params(), sig.Results()
+	if params.Len() != 1 || (results.Len() != 1 && !returnsError(sig)) {
+		return false
+	}
+
+	return types.AssignableTo(from, params.At(0).Type()) &&
+		types.AssignableTo(results.At(0).Type(), to)
+}
+
+func joinMethodNames(methods []*types.Func) string {
+	names := make([]string, len(methods))
+	for i, method := range methods {
+		names[i] = method.Name()
+	}
+
+	return strings.Join(names, ", ")
+}
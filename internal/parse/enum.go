@@ -0,0 +1,303 @@
+package parse
+
+import (
+	"fmt"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Example: `ACTIVE->StatusActive`, or `_->StatusUnknown` for the default.
+var juryrigEnumDetailsRegex = regexp.MustCompile(`^(.+)->(.+)$`)
+
+const enumDefaultSource = "_"
+
+// Create a switch from the enum directives of a method which converts
+// one enum-like type to another.
+func (c *typeChecker) createEnumConversion(fn Function, jrComments []rawComment) (Conversion, error) {
+	param := fn.Parameters[0]
+	conversion := EnumConversion{
+		From:    param.ResolvedType,
+		To:      fn.ResolvedResult,
+		Cases:   nil,
+		Default: "",
+		Imports: nil,
+	}
+
+	var errorOnUnknown bool
+
+	seen := make(map[string]bool)
+	prefixes := defaultEnumPrefixes(conversion.From, conversion.To)
+
+	for _, jrComment := range jrComments {
+		var name, details string
+		if err := extractRegex(juryrigDirectiveRegex, jrComment.text, &name, &details); err != nil {
+			return nil, fmt.Errorf("[%s] is not a valid juryrig directive: %w", jrComment.text, ErrSpec)
+		}
+
+		var err error
+
+		switch name {
+		case "enum":
+			err = c.addEnumCase(fn, &conversion, seen, details)
+		case "enumerror":
+			errorOnUnknown = true
+		case "enumprefix":
+			prefixes, err = parseEnumPrefixes(details)
+		default:
+			err = fmt.Errorf("%s is not supported for a non-struct result: %w", jrComment.text, ErrSpec)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid directive %s: %w", positionDebugInfo(jrComment.position), err)
+		}
+	}
+
+	// Anything not mapped explicitly is matched by name.
+	if err := c.matchEnumConstants(&conversion, seen, prefixes); err != nil {
+		return nil, err
+	}
+
+	return conversion, checkEnumFallback(fn, conversion, errorOnUnknown)
+}
+
+// Whether both types have constants to match up, such that a method
+// converting between them should be generated as a switch.
+func (c *typeChecker) isEnumPair(from, to types.Type) bool {
+	return len(c.enumConstants(from)) > 0 && len(c.enumConstants(to)) > 0
+}
+
+// The constants declared with the type, in declaration order.
+func (c *typeChecker) enumConstants(typ types.Type) []*types.Const {
+	named, ok := typ.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return nil
+	}
+
+	scope := named.Obj().Pkg().Scope()
+
+	var result []*types.Const
+
+	for _, name := range scope.Names() {
+		if obj, ok := scope.Lookup(name).(*types.Const); ok &&
+			types.Identical(obj.Type(), typ) && c.isAccessible(obj) {
+			result = append(result, obj)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Pos() < result[j].Pos()
+	})
+
+	return result
+}
+
+// enumPrefixes are stripped from the names of constants before they are
+// matched.
+type enumPrefixes struct {
+	source string
+	target string
+}
+
+// By default, the names of the types are stripped, e.g. Status for
+// StatusActive.
+func defaultEnumPrefixes(from, to types.Type) enumPrefixes {
+	return enumPrefixes{
+		source: typeName(from),
+		target: typeName(to),
+	}
+}
+
+// Example: `ExternalStatus->Status`. Either side may be empty.
+var juryrigEnumPrefixDetailsRegex = regexp.MustCompile(`^(\w*)->(\w*)$`)
+
+func parseEnumPrefixes(details string) (enumPrefixes, error) {
+	var prefixes enumPrefixes
+	if err := extractRegex(juryrigEnumPrefixDetailsRegex, details, &prefixes.source, &prefixes.target); err != nil {
+		return enumPrefixes{}, fmt.Errorf("[%s] is not valid config for the enumprefix directive: %w",
+			details, ErrSpec)
+	}
+
+	return prefixes, nil
+}
+
+// Add cases for the source constants which haven't been seen, by finding a
+// target constant with the same name (ignoring case and prefixes).
+func (c *typeChecker) matchEnumConstants(conversion *EnumConversion, seen map[string]bool, prefixes enumPrefixes) error {
+	sources, targets := c.enumConstants(conversion.From), c.enumConstants(conversion.To)
+	if len(sources) == 0 || len(targets) == 0 {
+		return nil
+	}
+
+	targetsByName := make(map[string]*types.Const, len(targets))
+	for _, target := range targets {
+		targetsByName[enumKey(target, prefixes.target)] = target
+	}
+
+	var unmatched []string
+
+	for _, source := range sources {
+		key := source.Val().ExactString()
+		if seen[key] {
+			// Mapped explicitly, or an alias of one which was matched.
+			continue
+		}
+
+		target, ok := targetsByName[enumKey(source, prefixes.source)]
+		if !ok {
+			unmatched = append(unmatched, source.Name())
+			continue
+		}
+
+		seen[key] = true
+
+		conversion.Imports = append(conversion.Imports, c.constImports(source, target)...)
+		conversion.Cases = append(conversion.Cases, EnumCase{
+			Source: c.constString(source),
+			Target: c.constString(target),
+		})
+	}
+
+	if len(unmatched) > 0 {
+		return fmt.Errorf("enum constants [%s] have no match in %s, please add enum directives: %w",
+			strings.Join(unmatched, ", "), c.typeString(conversion.To), ErrSpec)
+	}
+
+	return nil
+}
+
+func enumKey(obj *types.Const, prefix string) string {
+	return strings.ToLower(strings.TrimPrefix(obj.Name(), prefix))
+}
+
+// The constant as it would be written in the local package.
+func (c *typeChecker) constString(obj *types.Const) string {
+	if obj.Pkg() == c.pkg {
+		return obj.Name()
+	}
+
+	return fmt.Sprintf("%s.%s", c.packageName(obj.Pkg()), obj.Name())
+}
+
+func (c *typeChecker) constImports(constants ...*types.Const) []Import {
+	var result []Import
+
+	for _, obj := range constants {
+		if obj.Pkg() != c.pkg {
+			result = append(result, Import{
+				Name: c.packageName(obj.Pkg()),
+				Path: obj.Pkg().Path(),
+			})
+		}
+	}
+
+	return result
+}
+
+func typeName(typ types.Type) string {
+	if named, ok := typ.(*types.Named); ok {
+		return named.Obj().Name()
+	}
+
+	return ""
+}
+
+func (c *typeChecker) addEnumCase(fn Function, conversion *EnumConversion, seen map[string]bool, details string) error {
+	var source, target string
+	if err := extractRegex(juryrigEnumDetailsRegex, details, &source, &target); err != nil {
+		return fmt.Errorf("[%s] is not valid config for the enum directive: %w", details, ErrSpec)
+	}
+
+	source = strings.TrimSpace(source)
+
+	checkedTarget, err := c.checkEnumValue(fn, "juryrigResult", "the result", target)
+	if err != nil {
+		return err
+	}
+
+	conversion.Imports = append(conversion.Imports, checkedTarget.imports...)
+
+	if source == enumDefaultSource {
+		if conversion.Default != "" {
+			return fmt.Errorf("the enum default is already set to %s: %w", conversion.Default, ErrSpec)
+		}
+
+		conversion.Default = checkedTarget.src
+
+		return nil
+	}
+
+	param := fn.Parameters[0]
+
+	checkedSource, err := c.checkEnumValue(fn, param.Name, "parameter "+param.Name, source)
+	if err != nil && token.IsIdentifier(source) && isString(param.ResolvedType) {
+		// Bare words are taken to be strings, e.g. ACTIVE for "ACTIVE".
+		checkedSource, err = c.checkEnumValue(fn, param.Name, "parameter "+param.Name, strconv.Quote(source))
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// (a switch may not repeat a constant case)
+	key := checkedSource.value.ExactString()
+	if seen[key] {
+		return fmt.Errorf("enum value %s is already mapped: %w", checkedSource.src, ErrSpec)
+	}
+
+	seen[key] = true
+
+	conversion.Imports = append(conversion.Imports, checkedSource.imports...)
+	conversion.Cases = append(conversion.Cases, EnumCase{
+		Source: checkedSource.src,
+		Target: checkedTarget.src,
+	})
+
+	return nil
+}
+
+// Values which can't be matched either fall back to the default, or are
+// an error.
+func checkEnumFallback(fn Function, conversion EnumConversion, errorOnUnknown bool) error {
+	switch {
+	case conversion.Default != "" && errorOnUnknown:
+		return fmt.Errorf("%s cannot have both an enum default and enumerror: %w", fn.Name, ErrSpec)
+	case conversion.Default == "" && !errorOnUnknown:
+		return fmt.Errorf("%s needs an enum default (e.g. `+juryrig:enum:_->Unknown`), or enumerror: %w",
+			fn.Name, ErrSpec)
+	case errorOnUnknown && !fn.ReturnsError:
+		return fmt.Errorf("enumerror requires %s to return an error: %w", fn.Name, ErrSpec)
+	}
+
+	return nil
+}
+
+// enumValue is a constant from an enum directive.
+type enumValue struct {
+	src     string
+	value   constant.Value
+	imports []Import
+}
+
+func (c *typeChecker) checkEnumValue(fn Function, lhs string, description string, src string) (enumValue, error) {
+	src = strings.TrimSpace(src)
+
+	checked, err := c.checkAssignment(fn, lhs, description, src)
+	if err != nil {
+		return enumValue{}, err
+	}
+
+	if checked.typeAndValue.Value == nil {
+		return enumValue{}, fmt.Errorf("[%s] is not a constant: %w", src, ErrSpec)
+	}
+
+	return enumValue{
+		src:     src,
+		value:   checked.typeAndValue.Value,
+		imports: checked.imports,
+	}, nil
+}
@@ -0,0 +1,228 @@
+package parse
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+)
+
+// checkedExpr is a Go expression from a directive which has been type
+// checked.
+type checkedExpr struct {
+	typeAndValue types.TypeAndValue
+	imports      []Import
+	// The parameters (and their fields) which it refers to.
+	sources []Source
+}
+
+// Type check a Go expression as if it were assigned to the target of fn,
+// with fn's parameters (and the imports of the mapper file) in scope.
+func (c *typeChecker) checkExpr(fn Function, target Target, src string) (checkedExpr, error) {
+	lhs := "juryrigResult." + target.Field
+	if fn.UpdateTarget != "" {
+		lhs = fn.UpdateTarget + "." + target.Field
+	}
+
+	return c.checkAssignment(fn, lhs, "target "+target.Field, src)
+}
+
+// Type check a Go expression as if it were assigned to lhs within fn, where
+// the result of fn (if any) is named juryrigResult. The lhs is described for
+// errors.
+func (c *typeChecker) checkAssignment(fn Function, lhs string, description string, src string) (checkedExpr, error) {
+	// Make sure it is a lone expression before wrapping it up...
+	standalone, err := parser.ParseExpr(src)
+	if err != nil {
+		return checkedExpr{}, fmt.Errorf("[%s] is not a valid Go expression: %w", src, ErrSpec)
+	}
+
+	params := make([]string, len(fn.Parameters))
+	for i, param := range fn.Parameters {
+		params[i] = fmt.Sprintf("%s %s", param.Name, param.Type)
+	}
+
+	c.importPackages(fn, standalone)
+
+	// ...in a function which assigns it to the target, so that the
+	// assignment is checked exactly as the compiler would.
+	result := ""
+	if fn.Result != "" {
+		result = fmt.Sprintf("(juryrigResult %s)", fn.Result)
+	}
+
+	wrapper := fmt.Sprintf("func(%s) %s {\n%s = (%s)\nreturn\n}",
+		strings.Join(params, ", "), result, lhs, src)
+
+	parsed, err := parser.ParseExprFrom(c.fset, "directive", wrapper, 0)
+	if err != nil {
+		return checkedExpr{}, fmt.Errorf("[%s] is not a valid Go expression: %w", src, ErrSpec)
+	}
+
+	lit, _ := parsed.(*ast.FuncLit)
+	assign, _ := lit.Body.List[0].(*ast.AssignStmt)
+	expr, _ := assign.Rhs[0].(*ast.ParenExpr)
+
+	info := &types.Info{ //nolint:exhaustruct
+		Types: make(map[ast.Expr]types.TypeAndValue),
+		Uses:  make(map[*ast.Ident]types.Object),
+	}
+
+	if err := types.CheckExpr(c.fset, c.pkg, c.scope, lit, info); err != nil {
+		var typeErr types.Error
+		if errors.As(err, &typeErr) {
+			return checkedExpr{}, fmt.Errorf("[%s] is not valid for %s: %s: %w",
+				src, description, typeErr.Msg, ErrSpec)
+		}
+
+		return checkedExpr{}, fmt.Errorf("[%s] is not valid for %s: %w", src, description, err)
+	}
+
+	imports := usedImports(info)
+	for _, imp := range imports {
+		for _, generated := range generatedImports {
+			if imp.Path == generated && imp.Name != generated {
+				return checkedExpr{}, fmt.Errorf("[%s] refers to package %s as %s, but generated code refers to it "+
+					"as %s, please import it without an alias: %w", src, imp.Path, imp.Name, generated, ErrSpec)
+			}
+		}
+	}
+
+	return checkedExpr{
+		typeAndValue: info.Types[expr.X],
+		imports:      imports,
+		sources:      usedParameters(info, lit, expr.X),
+	}, nil
+}
+
+// The mapper file would not compile if it imported a package only for use in
+// directives, so packages which expr refers to but the file doesn't import
+// are looked up by name: first among the imports of the rest of the package,
+// and then as standard library paths. They are then added to the file's
+// scope, for checking.
+func (c *typeChecker) importPackages(fn Function, expr ast.Expr) {
+	fileScope := c.pkg.Scope().Innermost(c.scope)
+	if fileScope == nil {
+		return
+	}
+
+	ast.Inspect(expr, func(node ast.Node) bool {
+		selector, ok := node.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+
+		ident, ok := selector.X.(*ast.Ident)
+		if !ok {
+			return true
+		}
+
+		if _, isParam := findParameter(fn, ident.Name); isParam {
+			return true
+		}
+
+		if _, obj := fileScope.LookupParent(ident.Name, token.NoPos); obj != nil {
+			return true
+		}
+
+		if imported := c.findPackage(ident.Name); imported != nil {
+			fileScope.Insert(types.NewPkgName(token.NoPos, c.pkg, ident.Name, imported))
+		}
+
+		return true
+	})
+}
+
+// The name which the mapper file refers to pkg by, so that generated code
+// uses one name for each package.
+func (c *typeChecker) packageName(pkg *types.Package) string {
+	if fileScope := c.pkg.Scope().Innermost(c.scope); fileScope != nil {
+		for _, name := range fileScope.Names() {
+			if pkgName, ok := fileScope.Lookup(name).(*types.PkgName); ok && pkgName.Imported() == pkg {
+				return name
+			}
+		}
+	}
+
+	return pkg.Name()
+}
+
+// Note: result is nillable.
+func (c *typeChecker) findPackage(name string) *types.Package {
+	for _, imported := range c.pkg.Imports() {
+		if imported.Name() == name {
+			return imported
+		}
+	}
+
+	imported, err := c.importer.Import(name)
+	if err != nil || imported.Name() != name {
+		return nil
+	}
+
+	return imported
+}
+
+// The parameters of lit which expr refers to, including the field selected
+// from them (if any).
+func usedParameters(info *types.Info, lit *ast.FuncLit, expr ast.Expr) []Source {
+	params := lit.Type.Params
+	isParam := func(ident *ast.Ident) bool {
+		obj, ok := info.Uses[ident].(*types.Var)
+		return ok && obj.Pos() >= params.Pos() && obj.Pos() < params.End()
+	}
+
+	var result []Source
+
+	ast.Inspect(expr, func(node ast.Node) bool {
+		switch v := node.(type) {
+		case *ast.SelectorExpr:
+			if ident, ok := v.X.(*ast.Ident); ok && isParam(ident) {
+				result = append(result, Source{Parameter: ident.Name, Field: v.Sel.Name}) //nolint:exhaustruct
+				return false
+			}
+		case *ast.Ident:
+			if isParam(v) {
+				result = append(result, Source{Parameter: v.Name}) //nolint:exhaustruct
+			}
+		}
+
+		return true
+	})
+
+	return result
+}
+
+// The packages which checked code refers to, by the names it uses.
+func usedImports(info *types.Info) []Import {
+	seen := make(map[Import]bool)
+
+	var result []Import
+
+	for _, obj := range info.Uses {
+		pkgName, ok := obj.(*types.PkgName)
+		if !ok {
+			continue
+		}
+
+		imp := Import{
+			Name: pkgName.Name(),
+			Path: pkgName.Imported().Path(),
+		}
+		if !seen[imp] {
+			seen[imp] = true
+
+			result = append(result, imp)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Path < result[j].Path
+	})
+
+	return result
+}
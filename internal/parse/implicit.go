@@ -0,0 +1,115 @@
+package parse
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+)
+
+// Create link directives for the target fields which have not been
+// covered explicitly, by finding a parameter field with the same name and
+// a type which can be converted to the target's.
+func (c *typeChecker) implicitDirectives(fn Function, explicit []Directive, options MapperOptions) ([]Directive, error) {
+	covered := coveredTargets(explicit)
+
+	var result []Directive
+
+	for _, field := range c.taggedFields(fn.ResolvedResult, false) {
+		if _, ok := covered[field.Name()]; ok {
+			continue
+		}
+
+		// A juryrig tag of "-" ignores the field.
+		if field.tag.Get(juryrigTagKey) == "-" {
+			result = append(result, IgnoreDirective{Target: Target{Field: field.Name()}})
+			continue
+		}
+
+		name, ok := matchName(field, options.TargetTag)
+		if !ok {
+			continue
+		}
+
+		links := c.findImplicitLinks(fn, field.Var, options.NameMatching.key(name), options)
+
+		switch len(links) {
+		case 0:
+			continue
+		case 1:
+			result = append(result, links[0])
+		default:
+			return nil, fmt.Errorf("target field %s of %s could come from any of [%s], please add a directive: %w",
+				field.Name(), fn.Name, joinLinkSources(links), ErrSpec)
+		}
+	}
+
+	return result, nil
+}
+
+// Sources are matched by the key of their name, under the mapper's name
+// matching strategy.
+func (c *typeChecker) findImplicitLinks(fn Function, target *types.Var, key string, options MapperOptions) []LinkDirective {
+	var result []LinkDirective
+
+	for _, param := range sourceParameters(fn) {
+		for _, field := range c.taggedFields(param.ResolvedType, true) {
+			fieldName, ok := matchName(field, options.SourceTag)
+			if !ok || options.NameMatching.key(fieldName) != key {
+				continue
+			}
+
+			if link, ok := c.implicitLink(fn, param, field.Var, target); ok {
+				result = append(result, link)
+			}
+		}
+	}
+
+	return result
+}
+
+func (c *typeChecker) implicitLink(fn Function, param Parameter, field, target *types.Var) (LinkDirective, bool) {
+	source, from, err := c.resolveSource(fn, Source{
+		Parameter: param.Name,
+		Field:     field.Name(),
+		NilChecks: nil,
+		Type:      nil,
+	})
+	if err != nil {
+		return LinkDirective{}, false
+	}
+
+	conversion, err := c.resolveConversion(fn, from, target.Type(), conversionOptions{}) //nolint:exhaustruct
+	if err != nil {
+		return LinkDirective{}, false
+	}
+
+	return LinkDirective{
+		Source: source,
+		Target: Target{
+			Field: target.Name(),
+		},
+		AssumeNonNil:   false,
+		AllowNarrowing: false,
+		Format:         "",
+		Default:        "",
+		Conversion:     conversion,
+		DefaultImports: nil,
+	}, true
+}
+
+func joinLinkSources(links []LinkDirective) string {
+	strs := make([]string, len(links))
+	for i, link := range links {
+		strs[i] = formatSource(link.Source)
+	}
+
+	return strings.Join(strs, ", ")
+}
+
+func formatSource(source Source) string {
+	if source.Field == "" {
+		return source.Parameter
+	}
+
+	return fmt.Sprintf("%s.%s", source.Parameter, source.Field)
+}
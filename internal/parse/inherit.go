@@ -0,0 +1,219 @@
+package parse
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+)
+
+// Reuse the directives of another method for fn, with the parameters they
+// refer to renamed to fn's. Targets which are covered by fn's own
+// directives are left to them.
+func inheritDirectives(ctx mapperContext, fn Function, parentName string, explicit []Directive) ([]Directive, error) {
+	parentFn, options, jrComments, err := findMapperFunction(ctx, parentName)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.Inherit != "" {
+		return nil, fmt.Errorf("%s itself inherits from %s: %w", parentName, options.Inherit, ErrSpec)
+	}
+
+	renames := matchParameters(parentFn, fn)
+	covered := coveredTargets(explicit)
+
+	var result []Directive
+
+	for _, jrComment := range jrComments {
+		directive, err := createDirective(jrComment.text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid directive %s: %w", positionDebugInfo(jrComment.position), err)
+		}
+
+		if target, ok := DirectiveTarget(directive); ok && covered.overlaps(target) {
+			// (overridden)
+			continue
+		}
+
+		directive, err = renames.apply(directive)
+		if err == nil {
+			directive, err = ctx.checker.resolveDirective(fn, directive)
+		}
+
+		if target, ok := DirectiveTarget(directive); ok && err == nil {
+			err = covered.cover(target)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("directive %s cannot be inherited: %w",
+				positionDebugInfo(jrComment.position), err)
+		}
+
+		result = append(result, directive)
+	}
+
+	return result, nil
+}
+
+// Find another method of the mapper which builds a struct, along with its
+// options and the comments for its directives.
+func findMapperFunction(ctx mapperContext, name string) (Function, FunctionOptions, []rawComment, error) {
+	for _, rawFunc := range ctx.fns {
+		if rawFunc.name != name {
+			continue
+		}
+
+		fn, err := ctx.checker.resolveFunction(createMapperFunction(rawFunc))
+		if err != nil {
+			return Function{}, FunctionOptions{}, nil, err
+		}
+
+		if !isStruct(fn.ResolvedResult) {
+			return Function{}, FunctionOptions{}, nil, fmt.Errorf("%s does not build a struct: %w", name, ErrSpec)
+		}
+
+		options, jrComments, err := createFunctionOptions(rawFunc.jrComments)
+		if err != nil {
+			return Function{}, FunctionOptions{}, nil, err
+		}
+
+		return fn, options, jrComments, nil
+	}
+
+	return Function{}, FunctionOptions{}, nil, fmt.Errorf("%s is not a method of the mapper: %w", name, ErrSpec)
+}
+
+// parameterRenames maps the source parameters of one method to those of
+// another. Parameters without a counterpart map to "".
+type parameterRenames struct {
+	from  string
+	to    string
+	names map[string]string
+}
+
+// Parameters are matched by type where that is unambiguous, and otherwise
+// by position among those left over.
+func matchParameters(from, to Function) parameterRenames {
+	fromParams, toParams := sourceParameters(from), sourceParameters(to)
+	names := make(map[string]string, len(fromParams))
+	taken := make(map[string]bool, len(toParams))
+
+	for _, fromParam := range fromParams {
+		names[fromParam.Name] = ""
+
+		var matches []Parameter
+
+		for _, toParam := range toParams {
+			if types.Identical(fromParam.ResolvedType, toParam.ResolvedType) {
+				matches = append(matches, toParam)
+			}
+		}
+
+		if len(matches) == 1 && !taken[matches[0].Name] {
+			names[fromParam.Name] = matches[0].Name
+			taken[matches[0].Name] = true
+		}
+	}
+
+	// The rest are paired up in order.
+	var remaining []Parameter
+
+	for _, toParam := range toParams {
+		if !taken[toParam.Name] {
+			remaining = append(remaining, toParam)
+		}
+	}
+
+	for _, fromParam := range fromParams {
+		if names[fromParam.Name] == "" && len(remaining) > 0 {
+			names[fromParam.Name] = remaining[0].Name
+			remaining = remaining[1:]
+		}
+	}
+
+	return parameterRenames{
+		from:  from.Name,
+		to:    to.Name,
+		names: names,
+	}
+}
+
+// The directive, reading from the renamed parameters.
+func (r parameterRenames) apply(directive Directive) (Directive, error) {
+	var err error
+
+	switch v := directive.(type) {
+	case LinkDirective:
+		v.Source.Parameter, err = r.rename(v.Source.Parameter)
+		return v, err
+	case LinkFuncDirective:
+		sources := make([]Source, len(v.Sources))
+		for i, source := range v.Sources {
+			sources[i] = source
+			if sources[i].Parameter, err = r.rename(source.Parameter); err != nil {
+				return nil, err
+			}
+		}
+
+		v.Sources = sources
+
+		return v, nil
+	case ExprDirective:
+		v.Expression, err = r.renameExpr(v.Expression)
+		return v, err
+	}
+
+	return directive, nil
+}
+
+func (r parameterRenames) rename(name string) (string, error) {
+	renamed, ok := r.names[name]
+	if !ok {
+		return name, nil
+	}
+
+	if renamed == "" {
+		return "", fmt.Errorf("parameter %s of %s has no counterpart in %s: %w", name, r.from, r.to, ErrSpec)
+	}
+
+	return renamed, nil
+}
+
+// Rename the parameters which an expression refers to, leaving selected
+// names (e.g. fields) alone.
+func (r parameterRenames) renameExpr(src string) (string, error) {
+	expr, err := parser.ParseExpr(src)
+	if err != nil {
+		return "", fmt.Errorf("[%s] is not a valid Go expression: %w", src, ErrSpec)
+	}
+
+	selected := make(map[*ast.Ident]bool)
+
+	ast.Inspect(expr, func(node ast.Node) bool {
+		switch v := node.(type) {
+		case *ast.SelectorExpr:
+			selected[v.Sel] = true
+		case *ast.Ident:
+			if !selected[v] && err == nil {
+				v.Name, err = r.rename(v.Name)
+			}
+		}
+
+		return true
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := format.Node(&buf, token.NewFileSet(), expr); err != nil {
+		return "", fmt.Errorf("could not format [%s]: %w", src, err)
+	}
+
+	return buf.String(), nil
+}
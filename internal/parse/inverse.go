@@ -0,0 +1,130 @@
+package parse
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Derive directives for fn by swapping the sources and targets of the
+// directives of another method, e.g. ToExternalUser from ToInternalUser.
+// Targets which are covered by fn's own directives are left to them.
+func invertDirectives(ctx mapperContext, fn Function, forwardName string, explicit []Directive) ([]Directive, error) {
+	forwardFn, options, jrComments, err := findMapperFunction(ctx, forwardName)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.InverseOf != "" {
+		return nil, fmt.Errorf("%s is itself an inverse: %w", forwardName, ErrSpec)
+	}
+
+	forwardParams, params := sourceParameters(forwardFn), sourceParameters(fn)
+	if len(forwardParams) != 1 || len(params) != 1 {
+		return nil, fmt.Errorf("both %s and %s must have exactly one source parameter: %w",
+			forwardFn.Name, fn.Name, ErrSpec)
+	}
+
+	covered := coveredTargets(explicit)
+
+	var result []Directive
+
+	for _, jrComment := range jrComments {
+		directive, err := createDirective(jrComment.text)
+		if err == nil {
+			directive, err = ctx.checker.resolveDirective(forwardFn, directive)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid directive %s: %w", positionDebugInfo(jrComment.position), err)
+		}
+
+		inverted, err := invertDirective(directive, params[0].Name, covered)
+		if err == nil && inverted != nil {
+			inverted, err = ctx.checker.resolveDirective(fn, inverted)
+		}
+
+		if target, ok := DirectiveTarget(inverted); ok && err == nil {
+			err = covered.cover(target)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("directive %s cannot be inverted: %w",
+				positionDebugInfo(jrComment.position), err)
+		}
+
+		if inverted != nil {
+			result = append(result, inverted)
+		}
+	}
+
+	return result, nil
+}
+
+// The directive with its source and target swapped, reading from param.
+// Returns nil if there is nothing to invert, or if covered already sets
+// the target.
+func invertDirective(directive Directive, param string, covered targetCoverage) (Directive, error) {
+	var inverted Directive
+
+	switch v := directive.(type) {
+	case LinkDirective:
+		if v.Source.Field == "" {
+			return notInvertible(covered, "it links a whole parameter", v.Source)
+		}
+
+		inverted = LinkDirective{ //nolint:exhaustruct
+			Source: Source{Parameter: param, Field: v.Target.Field}, //nolint:exhaustruct
+			Target: Target{Field: v.Source.Field},
+			Format: v.Format,
+		}
+	case LinkFuncDirective:
+		if len(v.Sources) != 1 || v.Sources[0].Field == "" {
+			return notInvertible(covered, "it links more than a single field", v.Sources...)
+		}
+
+		if v.Inverse == "" {
+			return notInvertible(covered, "it has no inverse option", v.Sources...)
+		}
+
+		inverted = LinkFuncDirective{ //nolint:exhaustruct
+			Sources:      []Source{{Parameter: param, Field: v.Target.Field}}, //nolint:exhaustruct
+			FunctionName: v.Inverse,
+			Target:       Target{Field: v.Sources[0].Field},
+		}
+	case ExprDirective:
+		return notInvertible(covered, "it is an expression", v.Sources...)
+	case ConstDirective, IgnoreDirective:
+		// Nothing is read, so there is nothing to set.
+		return nil, nil
+	}
+
+	if target, _ := DirectiveTarget(inverted); covered.overlaps(target) {
+		return nil, nil
+	}
+
+	return inverted, nil
+}
+
+// A directive which can't be inverted is fine if the fields it reads are
+// all set by the inverse's own directives.
+func notInvertible(covered targetCoverage, reason string, sources ...Source) (Directive, error) {
+	var missing []string
+
+	for _, source := range sources {
+		if source.Field == "" {
+			// (a whole parameter can't be set by directives)
+			return nil, fmt.Errorf("%s: %w", reason, ErrSpec)
+		}
+
+		if !covered.overlaps(Target{Field: source.Field}) {
+			missing = append(missing, source.Field)
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil, nil
+	}
+
+	return nil, fmt.Errorf("%s, so [%s] must be set by directives instead: %w",
+		reason, strings.Join(missing, ", "), ErrSpec)
+}
@@ -0,0 +1,68 @@
+package parse
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+)
+
+type loadedPackage struct {
+	fset     *token.FileSet
+	file     *ast.File
+	types    *types.Package
+	importer types.Importer
+}
+
+// Parse the file containing the mappers along with the rest of its package,
+// and type check them together. Type errors are tolerated, since the package
+// may well reference code which we are yet to (re)generate.
+func loadPackage(filename string) (loadedPackage, error) {
+	dir := filepath.Dir(filename)
+
+	buildPkg, err := build.Default.ImportDir(dir, 0)
+	if err != nil {
+		return loadedPackage{}, fmt.Errorf("could not find package for %s: %w", filename, err)
+	}
+
+	// Parse the files
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, filename, nil, parser.ParseComments)
+	if err != nil {
+		return loadedPackage{}, fmt.Errorf("could not parse file %s: %w", filename, err)
+	}
+
+	files := []*ast.File{file}
+
+	for _, name := range buildPkg.GoFiles {
+		if name == filepath.Base(filename) {
+			continue
+		}
+
+		// Partial results are fine for the rest of the package.
+		sibling, _ := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if sibling != nil {
+			files = append(files, sibling)
+		}
+	}
+
+	// Type check
+	imp := importer.ForCompiler(fset, "source", nil)
+	conf := types.Config{ //nolint:exhaustruct
+		Importer: imp,
+		Error:    func(error) {},
+	}
+	pkg, _ := conf.Check(buildPkg.ImportPath, fset, files, nil)
+
+	return loadedPackage{
+		fset:     fset,
+		file:     file,
+		types:    pkg,
+		importer: imp,
+	}, nil
+}
@@ -0,0 +1,49 @@
+package parse
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// NameMatching decides how field names are compared for implicit mapping.
+type NameMatching string
+
+const (
+	// UserName only matches UserName.
+	MatchExact NameMatching = "exact"
+	// UserName matches username and USERNAME.
+	MatchCaseInsensitive NameMatching = "caseinsensitive"
+	// UserName matches username, user_name and user-name.
+	MatchNormalized NameMatching = "normalized"
+)
+
+// ParseNameMatching reads a NameMatching from its name.
+func ParseNameMatching(str string) (NameMatching, error) {
+	switch matching := NameMatching(str); matching {
+	case MatchExact, MatchCaseInsensitive, MatchNormalized:
+		return matching, nil
+	}
+
+	return "", fmt.Errorf("[%s] is not a valid name matching strategy (expected one of %s, %s, %s): %w",
+		str, MatchExact, MatchCaseInsensitive, MatchNormalized, ErrSpec)
+}
+
+// The form of name which is compared under the strategy.
+func (m NameMatching) key(name string) string {
+	switch m {
+	case MatchExact:
+	case MatchCaseInsensitive:
+		return strings.ToLower(name)
+	case MatchNormalized:
+		return strings.Map(func(r rune) rune {
+			if r == '_' || r == '-' {
+				return -1
+			}
+
+			return unicode.ToLower(r)
+		}, name)
+	}
+
+	return name
+}
@@ -0,0 +1,24 @@
+package parse
+
+import "fmt"
+
+// NullValues decides what update methods do with sources which are not set.
+type NullValues string
+
+const (
+	// Assign the target regardless, e.g. clearing it for a nil pointer.
+	NullValuesSet NullValues = "set"
+	// Leave the target as it is if the source is nil or the zero value.
+	NullValuesSkip NullValues = "skip"
+)
+
+// ParseNullValues reads a NullValues strategy from its name.
+func ParseNullValues(str string) (NullValues, error) {
+	switch strategy := NullValues(str); strategy {
+	case NullValuesSet, NullValuesSkip:
+		return strategy, nil
+	}
+
+	return "", fmt.Errorf("[%s] is not a valid null value strategy (expected one of %s, %s): %w",
+		str, NullValuesSet, NullValuesSkip, ErrSpec)
+}
@@ -0,0 +1,82 @@
+package parse
+
+import "go/types"
+
+func isNumeric(basic *types.Basic) bool {
+	return basic.Info()&types.IsNumeric != 0
+}
+
+// Whether every value of from can be represented exactly by to.
+func isWidening(from, to *types.Basic) bool {
+	switch {
+	case isInteger(from) && isInteger(to):
+		fromBits, toBits := integerBits(from, false), integerBits(to, true)
+		if isUnsigned(from) == isUnsigned(to) {
+			return fromBits <= toBits
+		}
+
+		// Unsigned values fit in a wider signed type, but negative values
+		// never fit in an unsigned one.
+		return isUnsigned(from) && fromBits < toBits
+	case isInteger(from) && isFloat(to):
+		return integerBits(from, false) <= mantissaBits(to)
+	case isFloat(from) && isFloat(to), isComplex(from) && isComplex(to):
+		return sizeRank(from) <= sizeRank(to)
+	}
+
+	return false
+}
+
+func isInteger(basic *types.Basic) bool {
+	return basic.Info()&types.IsInteger != 0
+}
+
+func isUnsigned(basic *types.Basic) bool {
+	return basic.Info()&types.IsUnsigned != 0
+}
+
+func isFloat(basic *types.Basic) bool {
+	return basic.Info()&types.IsFloat != 0
+}
+
+func isComplex(basic *types.Basic) bool {
+	return basic.Info()&types.IsComplex != 0
+}
+
+// The size of int, uint and uintptr depends on the platform, so they are
+// taken to be as large as possible when converting from them, and as small
+// as possible when converting to them.
+func integerBits(basic *types.Basic, asTarget bool) int {
+	switch basic.Kind() {
+	case types.Int8, types.Uint8:
+		return 8
+	case types.Int16, types.Uint16:
+		return 16
+	case types.Int32, types.Uint32:
+		return 32
+	case types.Int, types.Uint, types.Uintptr:
+		if asTarget {
+			return 32
+		}
+	}
+
+	return 64
+}
+
+// The number of integer bits a float can hold exactly.
+func mantissaBits(basic *types.Basic) int {
+	if basic.Kind() == types.Float32 {
+		return 24
+	}
+
+	return 53
+}
+
+func sizeRank(basic *types.Basic) int {
+	switch basic.Kind() {
+	case types.Float32, types.Complex64:
+		return 1
+	}
+
+	return 2
+}
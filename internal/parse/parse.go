@@ -3,23 +3,32 @@ package parse
 import (
 	"errors"
 	"fmt"
+	"go/token"
 	"regexp"
 	"strings"
 )
 
-// Read parses Go source into Mapper definitions.
-func Read(filename string) (JuryrigSpec, error) {
+// Read parses Go source into Mapper definitions. Mappers may override
+// the given default options.
+func Read(filename string, defaults MapperOptions) (JuryrigSpec, error) {
+	// Load the package, so that we can check against its types
+	loaded, err := loadPackage(filename)
+	if err != nil {
+		return JuryrigSpec{}, err
+	}
+
 	// Just read the raw details (don't want to deal with ast stuff here)
-	pkg, raw, err := extractRaw(filename)
+	pkg, raw, err := extractRaw(loaded.fset, loaded.file, filename)
 	if err != nil {
 		return JuryrigSpec{}, fmt.Errorf("could not extract raw: %w", err)
 	}
 
 	// Convert to Mapper types
 	mappers := make([]Mapper, len(raw))
+	reporter := &reporter{} //nolint:exhaustruct
 
 	for i, rawI := range raw {
-		mapper, err := convertRawToMapper(rawI)
+		mapper, err := convertRawToMapper(loaded, defaults, reporter, rawI)
 		if err != nil {
 			return JuryrigSpec{}, err
 		}
@@ -29,14 +38,55 @@ func Read(filename string) (JuryrigSpec, error) {
 
 	// Join
 	return JuryrigSpec{
-		Package: pkg,
-		Mappers: mappers,
+		Package:      pkg,
+		PackageTypes: loaded.types,
+		Mappers:      mappers,
+		Warnings:     reporter.warnings,
 	}, nil
 }
 
-func convertRawToMapper(raw rawMapperInfo) (Mapper, error) {
+// mapperContext holds what is needed to convert the functions of a mapper.
+type mapperContext struct {
+	checker  *typeChecker
+	options  MapperOptions
+	reporter *reporter
+	// All the functions of the mapper, for directives which refer to
+	// others.
+	fns []rawMapperFuncInfo
+}
+
+func convertRawToMapper(
+	loaded loadedPackage,
+	defaults MapperOptions,
+	reporter *reporter,
+	raw rawMapperInfo,
+) (Mapper, error) {
+	checker, err := newTypeChecker(loaded, raw.name)
+	if err != nil {
+		return Mapper{}, err
+	}
+
 	// Map pieces...
-	mapperFuncs, err := convertRawFuncsToMapperFuncs(raw.fns)
+	options, err := createMapperOptions(defaults, raw.topJrComments)
+	if err != nil {
+		return Mapper{}, fmt.Errorf("cannot create mapper for %s: %w",
+			raw.name, err)
+	}
+
+	implName, receiver, err := createMapperNames(checker, raw)
+	if err != nil {
+		return Mapper{}, fmt.Errorf("cannot create mapper for %s: %w",
+			raw.name, err)
+	}
+
+	ctx := mapperContext{
+		checker:  checker,
+		options:  options,
+		reporter: reporter,
+		fns:      raw.fns,
+	}
+
+	mapperFuncs, err := convertRawFuncsToMapperFuncs(ctx, raw.fns)
 	if err != nil {
 		return Mapper{}, fmt.Errorf("cannot create mapper for %s: %w",
 			raw.name, err)
@@ -45,15 +95,138 @@ func convertRawToMapper(raw rawMapperInfo) (Mapper, error) {
 	// ...and join.
 	return Mapper{
 		Name:            raw.name,
+		ImplName:        implName,
+		Receiver:        receiver,
+		Options:         options,
 		MapperFunctions: mapperFuncs,
 	}, nil
 }
 
-func convertRawFuncsToMapperFuncs(rawFuncs []rawMapperFuncInfo) ([]MapperFunction, error) {
+func createMapperOptions(defaults MapperOptions, jrComments []rawComment) (MapperOptions, error) {
+	options := defaults
+
+	for _, jrComment := range jrComments {
+		if err := applyMapperDirective(&options, jrComment.text); err != nil {
+			return MapperOptions{}, fmt.Errorf("invalid mapper directive %s: %w",
+				positionDebugInfo(jrComment.position), err)
+		}
+	}
+
+	return options, nil
+}
+
+func applyMapperDirective(options *MapperOptions, jrComment string) error {
+	// Parse the comment for raw details
+	var name, details string
+	if err := extractRegex(juryrigDirectiveRegex, jrComment, &name, &details); err != nil {
+		return fmt.Errorf("[%s] is not a valid juryrig directive: %w",
+			jrComment, ErrSpec)
+	}
+
+	// Delegate to more specific option parsing
+	var err error
+
+	switch name {
+	case "mapper":
+		// Just marks the interface.
+	case "impl", "receiver":
+		// Handled by createMapperNames.
+	case "unmapped":
+		options.UnmappedTargets, err = ParsePolicy(details)
+	case "unmappedsource":
+		options.UnmappedSources, err = ParsePolicy(details)
+	case "tag":
+		options.SourceTag, options.TargetTag, err = parseTagKeys(details)
+	case "match":
+		options.NameMatching, err = ParseNameMatching(details)
+	case "nullvalues":
+		options.NullValues, err = ParseNullValues(details)
+	default:
+		return fmt.Errorf("[%s] does not contain a recognized mapper directive: %w",
+			jrComment, ErrSpec)
+	}
+
+	return err
+}
+
+// The generated struct is named after the mapper, and the receiver of its
+// methods is impl, unless the mapper's directives say otherwise.
+func createMapperNames(checker *typeChecker, raw rawMapperInfo) (string, string, error) {
+	implName, receiver := raw.name+"Impl", "impl"
+
+	for _, jrComment := range raw.topJrComments {
+		var name, details string
+		if err := extractRegex(juryrigDirectiveRegex, jrComment.text, &name, &details); err != nil {
+			// Left for createMapperOptions to report.
+			continue
+		}
+
+		switch name {
+		case "impl":
+			implName = details
+		case "receiver":
+			receiver = details
+		default:
+			continue
+		}
+
+		if !token.IsIdentifier(details) {
+			return "", "", fmt.Errorf("invalid mapper directive %s: [%s] is not a valid name for the %s directive: %w",
+				positionDebugInfo(jrComment.position), details, name, ErrSpec)
+		}
+	}
+
+	if err := checker.checkImplName(implName); err != nil {
+		return "", "", err
+	}
+
+	if err := checker.checkReceiverName(receiver); err != nil {
+		return "", "", err
+	}
+
+	// Neither the receiver nor packages used by generated code may be
+	// shadowed.
+	for _, fn := range raw.fns {
+		for _, param := range fn.parameters {
+			if param.Name == receiver {
+				return "", "", fmt.Errorf("parameter %s of %s has the same name as the receiver, "+
+					"please rename it or set another with `+juryrig:receiver:<name>`: %w", param.Name, fn.name, ErrSpec)
+			}
+
+			if checker.isPackageName(param.Name) {
+				return "", "", fmt.Errorf("parameter %s of %s has the same name as a package which generated code "+
+					"may use, please rename it: %w", param.Name, fn.name, ErrSpec)
+			}
+		}
+	}
+
+	return implName, receiver, nil
+}
+
+// Example: `json`, for both sides, or `json->db`, for sources and targets
+// respectively. Either side may be empty.
+var juryrigTagKeysRegex = regexp.MustCompile(`^(\w*)->(\w*)$`)
+
+func parseTagKeys(details string) (string, string, error) {
+	if juryrigTagKeysRegex.MatchString(details) {
+		var source, target string
+		err := extractRegex(juryrigTagKeysRegex, details, &source, &target)
+
+		return source, target, err
+	}
+
+	if details == "" || strings.ContainsAny(details, " ,:\"") {
+		return "", "", fmt.Errorf("[%s] is not a valid tag key: %w", details, ErrSpec)
+	}
+
+	return details, details, nil
+}
+
+func convertRawFuncsToMapperFuncs(ctx mapperContext, rawFuncs []rawMapperFuncInfo) ([]MapperFunction, error) {
 	mapperFuncs := make([]MapperFunction, len(rawFuncs))
 
 	for i, rawFunc := range rawFuncs {
-		mapperFunc, err := convertRawFuncToMapperFunc(rawFunc)
+		mapperFunc, err := convertRawFuncToMapperFunc(ctx, rawFunc)
 		if err != nil {
 			return nil, err
 		}
@@ -64,38 +237,200 @@ func convertRawFuncsToMapperFuncs(rawFuncs []rawMapperFuncInfo) ([]MapperFunctio
 	return mapperFuncs, nil
 }
 
-func convertRawFuncToMapperFunc(rawFunc rawMapperFuncInfo) (MapperFunction, error) {
+func convertRawFuncToMapperFunc(ctx mapperContext, rawFunc rawMapperFuncInfo) (MapperFunction, error) {
 	// Map pieces...
-	mapperFn := createMapperFunction(rawFunc)
-	directives, err := createDirectives(rawFunc.jrComments)
+	mapperFn, err := ctx.checker.resolveFunction(createMapperFunction(rawFunc))
+	if err != nil {
+		return MapperFunction{}, err
+	}
 
+	options, jrComments, err := createFunctionOptions(rawFunc.jrComments)
 	if err != nil {
 		return MapperFunction{}, err
 	}
 
+	location := positionDebugInfo(rawFunc.position)
+	if err := ctx.checker.checkFunctionOptions(mapperFn, options); err != nil {
+		return MapperFunction{}, fmt.Errorf("invalid options for %s %s: %w",
+			mapperFn.Name, location, err)
+	}
+
+	if options.NullValues == "" && mapperFn.UpdateTarget != "" {
+		options.NullValues = ctx.options.NullValues
+	}
+
+	// Non-struct results must come from converting the parameter.
+	if !isStruct(mapperFn.ResolvedResult) {
+		conversion, err := createFunctionConversion(ctx, mapperFn, jrComments)
+		if err != nil {
+			return MapperFunction{}, fmt.Errorf("cannot generate %s %s: %w", mapperFn.Name, location, err)
+		}
+
+		return MapperFunction{
+			Function:   mapperFn,
+			Options:    options,
+			Directives: nil,
+			Conversion: conversion,
+		}, nil
+	}
+
+	directives, err := createDirectives(ctx.checker, mapperFn, jrComments)
+	if err != nil {
+		return MapperFunction{}, err
+	}
+
+	if options.Inherit != "" {
+		inherited, err := inheritDirectives(ctx, mapperFn, options.Inherit, directives)
+		if err != nil {
+			return MapperFunction{}, fmt.Errorf("cannot inherit from %s for %s %s: %w",
+				options.Inherit, mapperFn.Name, location, err)
+		}
+
+		directives = append(directives, inherited...)
+	}
+
+	if options.InverseOf != "" {
+		inverted, err := invertDirectives(ctx, mapperFn, options.InverseOf, directives)
+		if err != nil {
+			return MapperFunction{}, fmt.Errorf("cannot invert %s for %s %s: %w",
+				options.InverseOf, mapperFn.Name, location, err)
+		}
+
+		directives = append(directives, inverted...)
+	}
+
+	// Fill in whatever the directives don't cover...
+	implicit, err := ctx.checker.implicitDirectives(mapperFn, directives, ctx.options)
+	if err != nil {
+		return MapperFunction{}, err
+	}
+
+	directives = append(directives, implicit...)
+
+	// ...and check if anything is still left out.
+	if err := checkUnmappedTargets(ctx, mapperFn, directives, location); err != nil {
+		return MapperFunction{}, err
+	}
+
+	if err := checkUnusedSources(ctx, mapperFn, directives, location); err != nil {
+		return MapperFunction{}, err
+	}
+
 	// ...and join.
 	return MapperFunction{
 		Function:   mapperFn,
+		Options:    options,
 		Directives: directives,
+		Conversion: nil,
 	}, nil
 }
 
+func createFunctionConversion(ctx mapperContext, fn Function, jrComments []rawComment) (Conversion, error) {
+	if len(fn.Parameters) != 1 {
+		return nil, fmt.Errorf("a mapper function with a non-struct result must have exactly one parameter: %w",
+			ErrSpec)
+	}
+
+	// Directives can only describe an enum mapping here.
+	from := fn.Parameters[0].ResolvedType
+	if len(jrComments) > 0 || ctx.checker.isEnumPair(from, fn.ResolvedResult) {
+		return ctx.checker.createEnumConversion(fn, jrComments)
+	}
+
+	// (don't look for methods here - we'd find this one)
+	return ctx.checker.resolveValueConversion(fn, from, fn.ResolvedResult,
+		conversionOptions{}) //nolint:exhaustruct
+}
+
+// Some directives apply to the function as a whole. Apply those, and return
+// the rest for directive creation.
+func createFunctionOptions(jrComments []rawComment) (FunctionOptions, []rawComment, error) {
+	var options FunctionOptions
+
+	var remaining []rawComment
+
+	for _, jrComment := range jrComments {
+		applied, err := applyFunctionDirective(&options, jrComment.text)
+		if err != nil {
+			return FunctionOptions{}, nil, fmt.Errorf("invalid directive %s: %w",
+				positionDebugInfo(jrComment.position), err)
+		}
+
+		if !applied {
+			remaining = append(remaining, jrComment)
+		}
+	}
+
+	return options, remaining, nil
+}
+
+func applyFunctionDirective(options *FunctionOptions, jrComment string) (bool, error) {
+	var name, details string
+	if err := extractRegex(juryrigDirectiveRegex, jrComment, &name, &details); err != nil {
+		// Leave it for directive creation to report.
+		return false, nil //nolint:nilerr
+	}
+
+	switch name {
+	case "returnnil":
+		if details != "" {
+			return false, fmt.Errorf("[%s] does not take any config: %w", jrComment, ErrSpec)
+		}
+
+		options.ReturnNil = true
+	case "nullvalues":
+		strategy, err := ParseNullValues(details)
+		if err != nil {
+			return false, err
+		}
+
+		options.NullValues = strategy
+	case "inverse":
+		if !token.IsIdentifier(details) {
+			return false, fmt.Errorf("[%s] is not valid config for the inverse directive: %w", details, ErrSpec)
+		}
+
+		options.InverseOf = details
+	case "inherit":
+		if !token.IsIdentifier(details) {
+			return false, fmt.Errorf("[%s] is not valid config for the inherit directive: %w", details, ErrSpec)
+		}
+
+		options.Inherit = details
+	default:
+		return false, nil
+	}
+
+	return true, nil
+}
+
 func createMapperFunction(rawFunc rawMapperFuncInfo) Function {
 	return Function{
-		Name:       rawFunc.name,
-		Parameters: rawFunc.parameters,
-		Result:     rawFunc.result,
+		Name:         rawFunc.name,
+		Parameters:   rawFunc.parameters,
+		Result:       rawFunc.result,
+		ReturnsError: rawFunc.returnsError,
 	}
 }
 
-func createDirectives(jrComments []string) ([]Directive, error) {
+func createDirectives(checker *typeChecker, fn Function, jrComments []rawComment) ([]Directive, error) {
 	// Each comment should correspond to one directive.
 	directives := make([]Directive, len(jrComments))
+	covered := make(targetCoverage)
 
 	for i, jrComment := range jrComments {
-		directive, err := createDirective(jrComment)
+		directive, err := createDirective(jrComment.text)
+		if err == nil {
+			directive, err = checker.resolveDirective(fn, directive)
+		}
+
+		if target, ok := DirectiveTarget(directive); ok && err == nil {
+			err = covered.cover(target)
+		}
+
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("invalid directive %s: %w",
+				positionDebugInfo(jrComment.position), err)
 		}
 
 		directives[i] = directive
@@ -105,7 +440,7 @@ func createDirectives(jrComments []string) ([]Directive, error) {
 }
 
 // Example: `+juryrig:link:ef.runtime->runtime`.
-var juryrigDirectiveRegex = regexp.MustCompile(`\+juryrig:(\w+):(.+)`)
+var juryrigDirectiveRegex = regexp.MustCompile(`\+juryrig:(\w+)(?::(.+))?`)
 
 func createDirective(jrComment string) (Directive, error) {
 	// Parse the comment for raw details
@@ -123,39 +458,75 @@ func createDirective(jrComment string) (Directive, error) {
 		return createLinkFuncDirective(details)
 	case "ignore":
 		return createIgnoreDirective(details)
+	case "const":
+		return createConstDirective(details)
+	case "expr":
+		return createExprDirective(details)
 	default:
 		return nil, fmt.Errorf("[%s] does not contain a recognized directive: %w",
 			jrComment, ErrSpec)
 	}
 }
 
-var juryrigLinkDetailsRegex = regexp.MustCompile(`^(.+)->(\w+)$`)
+// Example: `ef.details.runtime->runtime,nonnil,default=90`.
+var juryrigLinkDetailsRegex = regexp.MustCompile(`^(.+)->(\w+(?:\.\w+)*)(?:,(.*))?$`)
 
 func createLinkDirective(details string) (LinkDirective, error) {
 	// Parse the details...
-	var sourceStr, targetStr string
-	if err := extractRegex(juryrigLinkDetailsRegex, details, &sourceStr, &targetStr); err != nil {
+	var sourceStr, targetStr, optionsStr string
+	if err := extractRegex(juryrigLinkDetailsRegex, details, &sourceStr, &targetStr, &optionsStr); err != nil {
 		return LinkDirective{}, fmt.Errorf("[%s] is not valid config for the link directive: %w",
 			details, ErrSpec)
 	}
 
 	source := parseSource(sourceStr)
-
-	// ...and join
-	return LinkDirective{
+	directive := LinkDirective{
 		Source: source,
 		Target: Target{
 			Field: targetStr,
 		},
-	}, nil
+	}
+
+	// ...and apply options.
+	options, err := parseDirectiveOptions(optionsStr)
+	if err != nil {
+		return LinkDirective{}, err
+	}
+
+	for key, value := range options {
+		switch key {
+		case "nonnil":
+			directive.AssumeNonNil = true
+		case "narrow":
+			directive.AllowNarrowing = true
+		case "format":
+			if value == "" {
+				return LinkDirective{}, fmt.Errorf("the format option requires a value: %w", ErrSpec)
+			}
+
+			directive.Format = value
+		case "default":
+			if value == "" {
+				return LinkDirective{}, fmt.Errorf("the default option requires a value: %w", ErrSpec)
+			}
+
+			directive.Default = value
+		default:
+			return LinkDirective{}, fmt.Errorf("[%s=%s] is not a valid option for the link directive: %w",
+				key, value, ErrSpec)
+		}
+	}
+
+	return directive, nil
 }
 
-var juryrigLinkFuncDetailsRegex = regexp.MustCompile(`^(\w+)->(\w+)->(\w+)$`)
+// Example: `ef.runtime->ToRuntime->runtime,inverse=FromRuntime`.
+var juryrigLinkFuncDetailsRegex = regexp.MustCompile(`^(.+)->(\w+)->(\w+(?:\.\w+)*)(?:,(.*))?$`)
 
 func createLinkFuncDirective(details string) (LinkFuncDirective, error) {
 	// Parse the details...
-	var from, fn, target string
-	if err := extractRegex(juryrigLinkFuncDetailsRegex, details, &from, &fn, &target); err != nil {
+	var from, fn, target, optionsStr string
+	if err := extractRegex(juryrigLinkFuncDetailsRegex, details, &from, &fn, &target, &optionsStr); err != nil {
 		return LinkFuncDirective{}, fmt.Errorf("[%s] is not valid config for the linkfunc directive: %w",
 			details, ErrSpec)
 	}
@@ -168,14 +539,37 @@ func createLinkFuncDirective(details string) (LinkFuncDirective, error) {
 		sources[i] = parseSource(sourceStr)
 	}
 
-	// ...and join.
-	return LinkFuncDirective{
+	directive := LinkFuncDirective{
 		Sources:      sources,
 		FunctionName: fn,
 		Target: Target{
 			Field: target,
 		},
-	}, nil
+	}
+
+	// ...and apply options.
+	options, err := parseDirectiveOptions(optionsStr)
+	if err != nil {
+		return LinkFuncDirective{}, err
+	}
+
+	for key, value := range options {
+		switch key {
+		case "nonnil":
+			directive.AssumeNonNil = true
+		case "inverse":
+			if !token.IsIdentifier(value) {
+				return LinkFuncDirective{}, fmt.Errorf("the inverse option requires a method name: %w", ErrSpec)
+			}
+
+			directive.Inverse = value
+		default:
+			return LinkFuncDirective{}, fmt.Errorf("[%s=%s] is not a valid option for the linkfunc directive: %w",
+				key, value, ErrSpec)
+		}
+	}
+
+	return directive, nil
 }
 
 func createIgnoreDirective(details string) (IgnoreDirective, error) {
@@ -192,7 +586,43 @@ func createIgnoreDirective(details string) (IgnoreDirective, error) {
 	}, nil
 }
 
-var juryrigFieldSourceRegex = regexp.MustCompile(`^(\w+).(\w+)$`)
+// Example: `"unknown"->director`. The value is everything before the last
+// arrow, so that it may contain arrows itself.
+var juryrigValueDetailsRegex = regexp.MustCompile(`^(.+)->(\w+(?:\.\w+)*)$`)
+
+func createConstDirective(details string) (ConstDirective, error) {
+	var value, target string
+	if err := extractRegex(juryrigValueDetailsRegex, details, &value, &target); err != nil {
+		return ConstDirective{}, fmt.Errorf("[%s] is not valid config for the const directive: %w",
+			details, ErrSpec)
+	}
+
+	return ConstDirective{
+		Value: strings.TrimSpace(value),
+		Target: Target{
+			Field: target,
+		},
+	}, nil
+}
+
+// Example: `strings.ToUpper(ef.title)->title`.
+func createExprDirective(details string) (ExprDirective, error) {
+	var expression, target string
+	if err := extractRegex(juryrigValueDetailsRegex, details, &expression, &target); err != nil {
+		return ExprDirective{}, fmt.Errorf("[%s] is not valid config for the expr directive: %w",
+			details, ErrSpec)
+	}
+
+	return ExprDirective{
+		Expression: strings.TrimSpace(expression),
+		Target: Target{
+			Field: target,
+		},
+	}, nil
+}
+
+// Example: `ef.meta.director.name`.
+var juryrigFieldSourceRegex = regexp.MustCompile(`^(\w+)\.(\w+(?:\.\w+)*)$`)
 
 func parseSource(str string) Source {
 	// Try it as a paramater-field variant
@@ -213,8 +643,65 @@ func parseSource(str string) Source {
 	}
 }
 
+// Options are given as a comma separated list of `key` or `key=value`
+// entries, where values may be quoted.
+func parseDirectiveOptions(str string) (map[string]string, error) {
+	result := make(map[string]string)
+	if strings.TrimSpace(str) == "" {
+		return result, nil
+	}
+
+	for _, option := range splitOutsideQuotes(str, ',') {
+		key, value, _ := strings.Cut(option, "=")
+		key = strings.TrimSpace(key)
+
+		if key == "" {
+			return nil, fmt.Errorf("[%s] is not a valid option: %w", option, ErrSpec)
+		}
+
+		if _, ok := result[key]; ok {
+			return nil, fmt.Errorf("option %s is given more than once: %w", key, ErrSpec)
+		}
+
+		result[key] = strings.TrimSpace(value)
+	}
+
+	return result, nil
+}
+
 // >>> String/regex helpers <<<
 
+func splitOutsideQuotes(str string, sep rune) []string {
+	var (
+		result  []string
+		current strings.Builder
+		quote   rune
+		escaped bool
+	)
+
+	for _, r := range str {
+		switch {
+		case escaped:
+			escaped = false
+		case quote != 0 && r == '\\' && quote != '`':
+			escaped = true
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote == 0 && (r == '"' || r == '\'' || r == '`'):
+			quote = r
+		case quote == 0 && r == sep:
+			result = append(result, current.String())
+			current.Reset()
+
+			continue
+		}
+
+		current.WriteRune(r)
+	}
+
+	return append(result, current.String())
+}
+
 func mapStrings(in []string, fn func(string) string) []string {
 	out := make([]string, len(in))
 	for i, str := range in {
@@ -0,0 +1,166 @@
+package parse
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+)
+
+// Policy decides what happens when a problem is found with a mapper.
+type Policy string
+
+const (
+	PolicyError  Policy = "error"
+	PolicyWarn   Policy = "warn"
+	PolicyIgnore Policy = "ignore"
+)
+
+// ParsePolicy reads a Policy from its name.
+func ParsePolicy(str string) (Policy, error) {
+	switch policy := Policy(str); policy {
+	case PolicyError, PolicyWarn, PolicyIgnore:
+		return policy, nil
+	}
+
+	return "", fmt.Errorf("[%s] is not a valid policy (expected one of %s, %s, %s): %w",
+		str, PolicyError, PolicyWarn, PolicyIgnore, ErrSpec)
+}
+
+// reporter applies policies to problems, collecting any warnings.
+type reporter struct {
+	warnings []string
+}
+
+func (r *reporter) report(policy Policy, problem string) error {
+	switch policy {
+	case PolicyError:
+		return fmt.Errorf("%s: %w", problem, ErrSpec)
+	case PolicyWarn:
+		r.warnings = append(r.warnings, problem)
+	case PolicyIgnore:
+	}
+
+	return nil
+}
+
+func (c *typeChecker) unmappedTargets(fn Function, directives []Directive) []string {
+	return c.unmappedTargetFields(fn.ResolvedResult, "", coveredTargets(directives))
+}
+
+func (c *typeChecker) unmappedTargetFields(typ types.Type, prefix string, covered targetCoverage) []string {
+	var result []string
+
+	for _, field := range c.targetFields(typ) {
+		path := prefix + field.Name()
+
+		whole, ok := covered[path]
+
+		switch {
+		case !ok:
+			result = append(result, path)
+		case !whole:
+			// Built inline, so check the nested fields as well.
+			result = append(result, c.unmappedTargetFields(field.Type(), path+".", covered)...)
+		}
+	}
+
+	return result
+}
+
+func checkUnmappedTargets(ctx mapperContext, fn Function, directives []Directive, location string) error {
+	unmapped := ctx.checker.unmappedTargets(fn, directives)
+	if len(unmapped) == 0 {
+		return nil
+	}
+
+	problem := fmt.Sprintf("target fields [%s] of %s are not mapped or ignored %s",
+		strings.Join(unmapped, ", "), fn.Name, location)
+
+	return ctx.reporter.report(ctx.options.UnmappedTargets, problem)
+}
+
+func (c *typeChecker) unusedSources(fn Function, directives []Directive) []string {
+	used := usedSources(directives)
+
+	var result []string
+
+	for _, param := range sourceParameters(fn) {
+		if used[param.Name] {
+			// Used in its entirety.
+			continue
+		}
+
+		for _, field := range c.targetFields(param.ResolvedType) {
+			name := fmt.Sprintf("%s.%s", param.Name, field.Name())
+			if !used[name] && !c.usesPromotedField(param, field, used) {
+				result = append(result, name)
+			}
+		}
+	}
+
+	return result
+}
+
+// An embedded field is used if any of the fields promoted from it are.
+func (c *typeChecker) usesPromotedField(param Parameter, field *types.Var, used map[string]bool) bool {
+	if !field.Embedded() {
+		return false
+	}
+
+	prefix := param.Name + "."
+
+	for name := range used {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		if _, embedded := c.lookupSourceField(param.ResolvedType, strings.TrimPrefix(name, prefix)); len(embedded) > 0 && embedded[0] == field {
+			return true
+		}
+	}
+
+	return false
+}
+
+func usedSources(directives []Directive) map[string]bool {
+	result := make(map[string]bool)
+	use := func(source Source) {
+		if source.Field == "" {
+			result[source.Parameter] = true
+			return
+		}
+
+		// Using part of a field counts as using the field.
+		field, _, _ := strings.Cut(source.Field, ".")
+		result[fmt.Sprintf("%s.%s", source.Parameter, field)] = true
+	}
+
+	for _, directive := range directives {
+		switch v := directive.(type) {
+		case LinkDirective:
+			use(v.Source)
+		case LinkFuncDirective:
+			for _, source := range v.Sources {
+				use(source)
+			}
+		case ExprDirective:
+			for _, source := range v.Sources {
+				use(source)
+			}
+		}
+	}
+
+	return result
+}
+
+func checkUnusedSources(ctx mapperContext, fn Function, directives []Directive, location string) error {
+	unused := ctx.checker.unusedSources(fn, directives)
+	if len(unused) == 0 {
+		return nil
+	}
+
+	problem := fmt.Sprintf("source fields [%s] of %s are not used %s",
+		strings.Join(unused, ", "), fn.Name, location)
+
+	return ctx.reporter.report(ctx.options.UnmappedSources, problem)
+}
@@ -4,7 +4,6 @@ import (
 	"errors"
 	"fmt"
 	"go/ast"
-	"go/parser"
 	"go/token"
 	"os"
 	"strings"
@@ -12,15 +11,22 @@ import (
 
 type rawMapperInfo struct {
 	name          string
-	topJrComments []string
+	topJrComments []rawComment
 	fns           []rawMapperFuncInfo
 }
 
 type rawMapperFuncInfo struct {
-	name       string
-	parameters []Parameter
-	result     string
-	jrComments []string
+	name         string
+	parameters   []Parameter
+	result       string
+	returnsError bool
+	jrComments   []rawComment
+	position     token.Position
+}
+
+type rawComment struct {
+	text     string
+	position token.Position
 }
 
 const (
@@ -34,15 +40,8 @@ var (
 
 // Just extract the most basic raw details from the files. Keep the
 // ast stuff here basically.
-func extractRaw(filename string) (string, []rawMapperInfo, error) {
-	// Read ast and files
-	fset := token.NewFileSet()
-	astFile, err := parser.ParseFile(fset, filename, nil, parser.ParseComments)
-
-	if err != nil {
-		return "", nil, fmt.Errorf("could not parse file %s: %w", filename, err)
-	}
-
+func extractRaw(fset *token.FileSet, astFile *ast.File, filename string) (string, []rawMapperInfo, error) {
+	// Read file
 	body, err := os.ReadFile(filename)
 
 	if err != nil {
@@ -104,7 +103,7 @@ func extractRawMapperInfo(fset *token.FileSet, astFile *ast.File, body []byte, d
 		return rawMapperInfo{}, fmt.Errorf("could not extract methods for mapper: %w", err)
 	}
 
-	comments := filterTaggedComments(genDecl.Doc.List, juryRigTag)
+	comments := filterTaggedComments(fset, genDecl.Doc, juryRigTag)
 
 	// ...and Map.
 	return rawMapperInfo{
@@ -193,7 +192,7 @@ func extractRawMapperFuncInfo(
 		return rawMapperFuncInfo{}, fmt.Errorf("could not extract func parameters: %w", err)
 	}
 
-	result, err := extractFuncResultType(astFile, body, funcType)
+	result, returnsError, err := extractFuncResultType(astFile, body, funcType)
 
 	if err != nil {
 		return rawMapperFuncInfo{}, err
@@ -201,10 +200,12 @@ func extractRawMapperFuncInfo(
 
 	// ...and Map.
 	return rawMapperFuncInfo{
-		name:       name,
-		parameters: params,
-		result:     result,
-		jrComments: filterTaggedComments(methodField.Doc.List, juryRigTag),
+		name:         name,
+		parameters:   params,
+		result:       result,
+		returnsError: returnsError,
+		jrComments:   filterTaggedComments(fset, methodField.Doc, juryRigTag),
+		position:     fset.Position(methodField.Pos()),
 	}, nil
 }
 
@@ -237,13 +238,35 @@ func extractFuncParamaters(
 	return result, nil
 }
 
-func extractFuncResultType(astFile *ast.File, body []byte, fn *ast.FuncType) (string, error) {
-	if len(fn.Results.List) != 1 {
-		return "", fmt.Errorf("mapper function must have exactly one result: %w",
-			ErrSpec)
+// Mapper functions have one result, optionally followed by an error. Those
+// which update a parameter in place have no result, or just an error.
+func extractFuncResultType(astFile *ast.File, body []byte, fn *ast.FuncType) (string, bool, error) {
+	var results []string
+
+	if fn.Results != nil {
+		for _, resultField := range fn.Results.List {
+			typ := readAsString(astFile, body, resultField.Type)
+			results = append(results, typ)
+			// (named results may share a type)
+			for i := 1; i < len(resultField.Names); i++ {
+				results = append(results, typ)
+			}
+		}
 	}
 
-	return readAsString(astFile, body, fn.Results.List[0].Type), nil
+	switch {
+	case len(results) == 0:
+		return "", false, nil
+	case len(results) == 1 && results[0] == "error":
+		return "", true, nil
+	case len(results) == 1:
+		return results[0], false, nil
+	case len(results) == 2 && results[1] == "error":
+		return results[0], true, nil
+	default:
+		return "", false, fmt.Errorf("mapper function must have one result, optionally followed by an error: %w",
+			ErrSpec)
+	}
 }
 
 func isJuryRigCommentGroup(commentGroup *ast.CommentGroup) bool {
@@ -262,12 +285,19 @@ func isJuryRigCommentGroup(commentGroup *ast.CommentGroup) bool {
 	return false
 }
 
-func filterTaggedComments(comments []*ast.Comment, tag string) []string {
-	var result []string
+func filterTaggedComments(fset *token.FileSet, commentGroup *ast.CommentGroup, tag string) []rawComment {
+	if commentGroup == nil {
+		return nil
+	}
+
+	var result []rawComment
 
-	for _, cmt := range comments {
+	for _, cmt := range commentGroup.List {
 		if isTaggedComment(cmt, tag) {
-			result = append(result, strings.TrimSpace(cmt.Text))
+			result = append(result, rawComment{
+				text:     strings.TrimSpace(cmt.Text),
+				position: fset.Position(cmt.Pos()),
+			})
 		}
 	}
 
@@ -279,7 +309,10 @@ func isTaggedComment(comment *ast.Comment, tag string) bool {
 }
 
 func locationDebugInfo(fset *token.FileSet, node ast.Node) string {
-	position := fset.Position(node.Pos())
+	return positionDebugInfo(fset.Position(node.Pos()))
+}
+
+func positionDebugInfo(position token.Position) string {
 	return fmt.Sprintf("[file %s, line %d]", position.Filename, position.Line)
 }
 
@@ -0,0 +1,92 @@
+package parse
+
+import (
+	"go/types"
+	"reflect"
+	"strings"
+)
+
+// The struct tag which names a field for implicit mapping, regardless of
+// the mapper's options.
+const juryrigTagKey = "juryrig"
+
+// taggedField is a field along with its struct tag.
+type taggedField struct {
+	*types.Var
+	tag reflect.StructTag
+}
+
+// The fields of typ which could be set or read, with their tags. Promoted
+// fields are included if asked for, unless they are shadowed.
+func (c *typeChecker) taggedFields(typ types.Type, promoted bool) []taggedField {
+	structType, ok := deref(typ).Underlying().(*types.Struct)
+	if !ok {
+		return nil
+	}
+
+	var (
+		result   []taggedField
+		embedded []*types.Var
+	)
+
+	seen := make(map[string]bool)
+
+	for i := 0; i < structType.NumFields(); i++ {
+		field := structType.Field(i)
+		seen[field.Name()] = true
+
+		if field.Embedded() && promoted {
+			embedded = append(embedded, field)
+		}
+
+		if c.isAccessible(field) {
+			result = append(result, taggedField{
+				Var: field,
+				tag: reflect.StructTag(structType.Tag(i)),
+			})
+		}
+	}
+
+	// Fields promoted from embedded structs come after the direct ones.
+	for _, field := range embedded {
+		for _, promotedField := range c.taggedFields(field.Type(), true) {
+			if !seen[promotedField.Name()] {
+				seen[promotedField.Name()] = true
+
+				result = append(result, promotedField)
+			}
+		}
+	}
+
+	return result
+}
+
+// The name a field is matched by for implicit mapping: its juryrig tag,
+// then its tag with tagKey (if set), and otherwise its Go name. Fields
+// tagged "-" are not matched at all.
+func matchName(field taggedField, tagKey string) (string, bool) {
+	for _, key := range []string{juryrigTagKey, tagKey} {
+		if key == "" {
+			continue
+		}
+
+		value, ok := field.tag.Lookup(key)
+		if !ok {
+			continue
+		}
+
+		// (options like omitempty follow the name)
+		name, _, _ := strings.Cut(value, ",")
+
+		switch name {
+		case "-":
+			return "", false
+		case "":
+			continue
+		}
+
+		return name, true
+	}
+
+	return field.Name(), true
+}
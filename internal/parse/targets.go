@@ -0,0 +1,88 @@
+package parse
+
+import (
+	"fmt"
+	"strings"
+)
+
+// targetCoverage records which target paths are set by directives. A path
+// is true if it is set in its entirety, and false if only some of its
+// nested fields are set.
+type targetCoverage map[string]bool
+
+func coveredTargets(directives []Directive) targetCoverage {
+	result := make(targetCoverage, len(directives))
+
+	for _, directive := range directives {
+		if target, ok := DirectiveTarget(directive); ok {
+			// Conflicts are checked as the directives are created.
+			_ = result.cover(target)
+		}
+	}
+
+	return result
+}
+
+func (tc targetCoverage) cover(target Target) error {
+	if whole, ok := tc[target.Field]; ok {
+		if !whole {
+			return fmt.Errorf("fields within target %s are already set by other directives: %w",
+				target.Field, ErrSpec)
+		}
+
+		return fmt.Errorf("target %s is already set by another directive: %w",
+			target.Field, ErrSpec)
+	}
+
+	// Mark the parents as partially set...
+	segments := strings.Split(target.Field, ".")
+	for i := 1; i < len(segments); i++ {
+		parent := strings.Join(segments[:i], ".")
+		if tc[parent] {
+			return fmt.Errorf("target %s is within %s, which is already set by another directive: %w",
+				target.Field, parent, ErrSpec)
+		}
+
+		tc[parent] = false
+	}
+
+	// ...and the target as wholly set.
+	tc[target.Field] = true
+
+	return nil
+}
+
+// Whether any part of the target is already set, wholly or in part.
+func (tc targetCoverage) overlaps(target Target) bool {
+	if _, ok := tc[target.Field]; ok {
+		return true
+	}
+
+	segments := strings.Split(target.Field, ".")
+	for i := 1; i < len(segments); i++ {
+		if tc[strings.Join(segments[:i], ".")] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// DirectiveTarget returns the target which directive sets (or ignores), if
+// it has one.
+func DirectiveTarget(directive Directive) (Target, bool) {
+	switch v := directive.(type) {
+	case LinkDirective:
+		return v.Target, true
+	case LinkFuncDirective:
+		return v.Target, true
+	case ConstDirective:
+		return v.Target, true
+	case ExprDirective:
+		return v.Target, true
+	case IgnoreDirective:
+		return v.Target, true
+	}
+
+	return Target{}, false
+}
@@ -1,20 +1,63 @@
 package parse
 
+import "go/types"
+
 // High-level mapper types
 
 type JuryrigSpec struct {
-	Package string
-	Mappers []Mapper
+	Package      string
+	PackageTypes *types.Package
+	Mappers      []Mapper
+	Warnings     []string
 }
 
 type Mapper struct {
-	Name            string
+	Name string
+	// The name of the generated struct which implements the mapper...
+	ImplName string
+	// ...and of the receiver of its methods.
+	Receiver        string
+	Options         MapperOptions
 	MapperFunctions []MapperFunction
 }
 
+// MapperOptions are set globally, and can be overridden per mapper.
+type MapperOptions struct {
+	// What to do with target fields that are neither mapped nor ignored.
+	UnmappedTargets Policy
+	// What to do with source fields that are not used.
+	UnmappedSources Policy
+	// Optional: the struct tags to match source and target fields by for
+	// implicit mapping, instead of their names.
+	SourceTag string
+	TargetTag string
+	// How names are compared for implicit mapping.
+	NameMatching NameMatching
+	// What update methods do with sources which are not set.
+	NullValues NullValues
+}
+
 type MapperFunction struct {
 	Function   Function
+	Options    FunctionOptions
 	Directives []Directive
+	// Set if the function converts its parameter as a whole (e.g. a
+	// slice), rather than building a struct from directives.
+	Conversion Conversion
+}
+
+// FunctionOptions are set by directives which apply to the function as a
+// whole, rather than to a target field.
+type FunctionOptions struct {
+	// Return nil when all pointer parameters are nil.
+	ReturnNil bool
+	// What an update method does with sources which are not set. Falls
+	// back to the mapper's strategy.
+	NullValues NullValues
+	// Optional: a method whose directives are inverted for this one.
+	InverseOf string
+	// Optional: a method whose directives are reused for this one.
+	Inherit string
 }
 
 // Common types
@@ -23,6 +66,11 @@ type Source struct {
 	Parameter string
 	// Optional
 	Field string
+	// Resolved by type checking: the pointers along the path which must
+	// be checked for nil, e.g. ef.details.
+	NilChecks []string
+	// Resolved by type checking.
+	Type types.Type
 }
 
 type Target struct {
@@ -32,12 +80,28 @@ type Target struct {
 type Function struct {
 	Name       string
 	Parameters []Parameter
-	Result     string
+	// Empty if the function updates a parameter in place.
+	Result string
+	// Resolved from Result by type checking, or the type of UpdateTarget.
+	ResolvedResult types.Type
+	// If the result is followed by an error (or is an error, for updates).
+	ReturnsError bool
+	// Resolved by type checking: the (last) parameter which is updated in
+	// place, if there is no result.
+	UpdateTarget string
+}
+
+// Import is a package as referred to by the mapper file.
+type Import struct {
+	Name string
+	Path string
 }
 
 type Parameter struct {
 	Name string
 	Type string
+	// Resolved from Type by type checking.
+	ResolvedType types.Type
 }
 
 // Directive indicates how to handle a given target field on a struct.
@@ -46,6 +110,18 @@ type Directive interface{}
 type LinkDirective struct {
 	Source Source
 	Target Target
+	// Skip nil checks, since the caller guarantees the source path is set.
+	AssumeNonNil bool
+	// Allow numeric conversions which may lose information.
+	AllowNarrowing bool
+	// Optional: the format for built-in converters, e.g. rfc3339.
+	Format string
+	// Optional: a constant to set the target to instead, when the source is
+	// the zero value (or can't be reached).
+	Default string
+	// Resolved by type checking.
+	Conversion     Conversion
+	DefaultImports []Import
 }
 
 var _ Directive = &LinkDirective{} //nolint:exhaustruct
@@ -56,10 +132,140 @@ type IgnoreDirective struct {
 
 var _ Directive = &IgnoreDirective{} //nolint:exhaustruct
 
+// ConstDirective sets the target to a constant, such as a literal or a
+// package-level constant.
+type ConstDirective struct {
+	Value  string
+	Target Target
+	// Resolved by type checking: the packages which Value refers to.
+	Imports []Import
+}
+
+var _ Directive = &ConstDirective{} //nolint:exhaustruct
+
+// ExprDirective sets the target to a Go expression, which may refer to the
+// parameters of the method.
+type ExprDirective struct {
+	Expression string
+	Target     Target
+	// Resolved by type checking: the packages and sources which Expression
+	// refers to.
+	Imports []Import
+	Sources []Source
+}
+
+var _ Directive = &ExprDirective{} //nolint:exhaustruct
+
 type LinkFuncDirective struct {
 	Sources      []Source
 	FunctionName string
 	Target       Target
+	// Optional: a method which undoes FunctionName, so that the directive
+	// can be inverted.
+	Inverse string
+	// Skip the nil checks for pointers along the source paths.
+	AssumeNonNil bool
+	// Resolved by type checking.
+	ReturnsError bool
 }
 
 var _ Directive = &LinkFuncDirective{} //nolint:exhaustruct
+
+// Conversion describes how a source value is turned into a target value.
+// A nil Conversion means the value is assigned as is.
+type Conversion interface{}
+
+// MethodConversion calls a method of the mapper with the value.
+type MethodConversion struct {
+	Method       string
+	ReturnsError bool
+}
+
+var _ Conversion = &MethodConversion{} //nolint:exhaustruct
+
+// TypeConversion is a Go conversion to Type, e.g. int64(v).
+type TypeConversion struct {
+	Type types.Type
+}
+
+var _ Conversion = &TypeConversion{} //nolint:exhaustruct
+
+// BuiltinConversion uses one of the converters which JuryRig provides for
+// standard types.
+type BuiltinConversion struct {
+	Builtin Builtin
+	From    types.Type
+	To      types.Type
+	// The time layout or duration unit, as a Go expression.
+	Format       string
+	ReturnsError bool
+}
+
+var _ Conversion = &BuiltinConversion{} //nolint:exhaustruct
+
+// Builtin identifies a built-in converter.
+type Builtin string
+
+const (
+	BuiltinFormatTime    Builtin = "formattime"
+	BuiltinParseTime     Builtin = "parsetime"
+	BuiltinDurationToInt Builtin = "durationtoint"
+	BuiltinIntToDuration Builtin = "inttoduration"
+	BuiltinFormatInt     Builtin = "formatint"
+	BuiltinParseInt      Builtin = "parseint"
+)
+
+// DerefConversion converts what a pointer points to, or leaves the target
+// as the zero value if the pointer is nil.
+type DerefConversion struct {
+	Type types.Type
+	Elem Conversion
+}
+
+var _ Conversion = &DerefConversion{} //nolint:exhaustruct
+
+// AddressConversion converts a value, and takes the address of the result.
+type AddressConversion struct {
+	Elem Conversion
+}
+
+var _ Conversion = &AddressConversion{} //nolint:exhaustruct
+
+// SliceConversion converts each element of a slice into a new slice.
+type SliceConversion struct {
+	Type types.Type
+	Elem Conversion
+}
+
+var _ Conversion = &SliceConversion{} //nolint:exhaustruct
+
+// MapConversion converts each key and element of a map into a new map.
+type MapConversion struct {
+	Type types.Type
+	Key  Conversion
+	Elem Conversion
+}
+
+var _ Conversion = &MapConversion{} //nolint:exhaustruct
+
+// EnumConversion maps constants of one type to constants of another, with a
+// switch.
+type EnumConversion struct {
+	From  types.Type
+	To    types.Type
+	Cases []EnumCase
+	// Optional: the value for anything without a case. If not set, an error
+	// is returned instead.
+	Default string
+	// The packages which the cases refer to.
+	Imports []Import
+}
+
+var _ Conversion = &EnumConversion{} //nolint:exhaustruct
+
+// EnumCase maps a source constant to a target constant, both given as Go
+// expressions.
+type EnumCase struct {
+	Source string
+	Target string
+}
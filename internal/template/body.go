@@ -0,0 +1,243 @@
+package template
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/liampulles/juryrig/internal/parse"
+)
+
+// funcBuilder collects the statements which need to run before a
+// function returns its result.
+type funcBuilder struct {
+	imports  *imports
+	receiver string
+	fn       parse.Function
+	names    *namer
+	stmts    []string
+}
+
+func newFuncBuilder(imports *imports, receiver string, in parse.MapperFunction) *funcBuilder {
+	reserved := []string{receiver, "err"}
+	for _, param := range in.Function.Parameters {
+		reserved = append(reserved, param.Name)
+	}
+
+	// Code copied from directives may refer to packages which nothing else
+	// imports.
+	for _, pkg := range copiedImports(in) {
+		reserved = append(reserved, pkg.Name)
+	}
+
+	return &funcBuilder{
+		imports:  imports,
+		receiver: receiver,
+		fn:       in.Function,
+		names:    newNamer(imports.localScope(), append(reserved, imports.packageNames()...)...),
+		stmts:    nil,
+	}
+}
+
+// The packages referred to by code copied from the function's directives.
+func copiedImports(in parse.MapperFunction) []parse.Import {
+	var result []parse.Import
+
+	if enum, ok := in.Conversion.(parse.EnumConversion); ok {
+		result = append(result, enum.Imports...)
+	}
+
+	for _, directive := range in.Directives {
+		switch v := directive.(type) {
+		case parse.LinkDirective:
+			result = append(result, v.DefaultImports...)
+		case parse.ConstDirective:
+			result = append(result, v.Imports...)
+		case parse.ExprDirective:
+			result = append(result, v.Imports...)
+		}
+	}
+
+	return result
+}
+
+func (b *funcBuilder) addStmt(format string, args ...interface{}) {
+	b.stmts = append(b.stmts, fmt.Sprintf(format, args...))
+}
+
+// A builder for statements in a nested block, such as a loop body.
+func (b *funcBuilder) nested() *funcBuilder {
+	return &funcBuilder{
+		imports:  b.imports,
+		receiver: b.receiver,
+		fn:       b.fn,
+		names:    b.names,
+		stmts:    nil,
+	}
+}
+
+// Call a function returning (T, error), assigning T to a new local variable.
+// Any error is wrapped with the label and returned.
+func (b *funcBuilder) callChecked(base string, call string, label errLabel) string {
+	name := b.names.fresh(base)
+	wrapped := "err"
+	if label.format != "" {
+		wrapped = label.errorf(b.imports.add("fmt"), "err")
+	}
+
+	b.addStmt("%s, err := %s\nif err != nil {\n%s\n}", name, call, b.returnError(wrapped))
+
+	return name
+}
+
+// Return statements for when the function fails...
+func (b *funcBuilder) returnError(err string) string {
+	if b.fn.UpdateTarget != "" {
+		return "return " + err
+	}
+
+	return fmt.Sprintf("return %s, %s", zeroValue(b.imports, b.fn.ResolvedResult), err)
+}
+
+// ...and for when it succeeds.
+func (b *funcBuilder) returnResult(result string) string {
+	if b.fn.UpdateTarget != "" {
+		if b.fn.ReturnsError {
+			return "return nil"
+		}
+
+		return ""
+	}
+
+	if b.fn.ReturnsError {
+		return fmt.Sprintf("return %s, nil", result)
+	}
+
+	return "return " + result
+}
+
+func (b *funcBuilder) body(result string) string {
+	stmts := b.stmts
+	if ret := b.returnResult(result); ret != "" {
+		stmts = append(stmts, ret)
+	}
+
+	return strings.Join(stmts, "\n\n")
+}
+
+func zeroValue(imports *imports, typ types.Type) string {
+	switch underlying := typ.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case underlying.Info()&types.IsBoolean != 0:
+			return "false"
+		case underlying.Info()&types.IsString != 0:
+			return `""`
+		case underlying.Info()&types.IsNumeric != 0:
+			return "0"
+		}
+	case *types.Struct, *types.Array:
+		return imports.typeString(typ, "") + "{}"
+	}
+
+	return "nil"
+}
+
+// errLabel describes where a value ends up in the result, so that errors
+// can be wrapped with it.
+type errLabel struct {
+	format string
+	args   []string
+}
+
+func targetLabel(target parse.Target) errLabel {
+	return errLabel{
+		format: target.Field,
+		args:   nil,
+	}
+}
+
+// The label of an element, at the index held in the variable i.
+func (l errLabel) index(i string) errLabel {
+	return errLabel{
+		format: l.format + "[%d]",
+		args:   append(append([]string{}, l.args...), i),
+	}
+}
+
+// The label of a map element, at the key held in the variable k.
+func (l errLabel) key(k string) errLabel {
+	return errLabel{
+		format: l.format + "[%v]",
+		args:   append(append([]string{}, l.args...), k),
+	}
+}
+
+// The label as the start of an error message, if there is one.
+func (l errLabel) prefix() string {
+	if l.format == "" {
+		return ""
+	}
+
+	return l.format + ": "
+}
+
+func (l errLabel) errorf(fmtPkg string, err string) string {
+	args := append(append([]string{}, l.args...), err)
+	return fmt.Sprintf(`%s.Errorf("%s: %%w", %s)`, fmtPkg, l.format, strings.Join(args, ", "))
+}
+
+// Turn a target path like user.account into a variable name like
+// userAccount.
+func localName(path string) string {
+	segments := strings.Split(path, ".")
+	for i, segment := range segments {
+		first, size := utf8.DecodeRuneInString(segment)
+		if i == 0 {
+			segments[i] = string(unicode.ToLower(first)) + segment[size:]
+		} else {
+			segments[i] = string(unicode.ToUpper(first)) + segment[size:]
+		}
+	}
+
+	return strings.Join(segments, "")
+}
+
+// namer hands out local variable names which don't clash with each
+// other, or with anything else the generated function might refer to.
+type namer struct {
+	used map[string]bool
+	// Optional: the package's own identifiers, such as its types.
+	scope *types.Scope
+}
+
+func newNamer(scope *types.Scope, reserved ...string) *namer {
+	used := make(map[string]bool, len(reserved))
+	for _, name := range reserved {
+		used[name] = true
+	}
+
+	return &namer{
+		used:  used,
+		scope: scope,
+	}
+}
+
+func (n *namer) fresh(base string) string {
+	name := base
+	for i := 2; n.taken(name); i++ {
+		name = fmt.Sprintf("%s%d", base, i)
+	}
+
+	n.used[name] = true
+
+	return name
+}
+
+func (n *namer) taken(name string) bool {
+	return n.used[name] || token.IsKeyword(name) || types.Universe.Lookup(name) != nil ||
+		(n.scope != nil && n.scope.Lookup(name) != nil)
+}
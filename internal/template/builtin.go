@@ -0,0 +1,134 @@
+package template
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"github.com/liampulles/juryrig/internal/parse"
+)
+
+func mapBuiltinConversion(b *funcBuilder, in parse.BuiltinConversion, value string, base string, label errLabel) string {
+	switch in.Builtin {
+	case parse.BuiltinFormatTime:
+		if strings.HasPrefix(in.Format, "time.") {
+			b.imports.add("time")
+		}
+
+		if strings.HasPrefix(value, "*") {
+			// (so that the method applies to what the pointer points to)
+			value = fmt.Sprintf("(%s)", value)
+		}
+
+		return convertTo(b.imports, fmt.Sprintf("%s.Format(%s)", value, in.Format), types.Typ[types.String], in.To)
+	case parse.BuiltinParseTime:
+		b.imports.add("time")
+		call := fmt.Sprintf("time.Parse(%s, %s)", in.Format, convertTo(b.imports, value, in.From, types.Typ[types.String]))
+
+		return b.callChecked(base, call, label)
+	case parse.BuiltinDurationToInt:
+		b.imports.add("time")
+		return mapTypeConversion(b.imports, parse.TypeConversion{Type: in.To}, fmt.Sprintf("%s / %s", value, in.Format))
+	case parse.BuiltinIntToDuration:
+		b.imports.add("time")
+		return fmt.Sprintf("%s * %s", mapTypeConversion(b.imports, parse.TypeConversion{Type: in.To}, value), in.Format)
+	case parse.BuiltinFormatInt:
+		return convertTo(b.imports, mapFormatInt(b.imports, in.From, value), types.Typ[types.String], in.To)
+	case parse.BuiltinParseInt:
+		return mapParseInt(b, in, value, base, label)
+	}
+	// Should be handled by parse stage...
+	return fmt.Sprintf("<<ERROR: UNKNOWN BUILTIN %s>>", in.Builtin)
+}
+
+func mapFormatInt(imports *imports, from types.Type, value string) string {
+	strconvPkg := imports.add("strconv")
+
+	switch {
+	case types.Identical(from, types.Typ[types.Int]):
+		return fmt.Sprintf("%s.Itoa(%s)", strconvPkg, value)
+	case isUnsigned(from):
+		return fmt.Sprintf("%s.FormatUint(%s, 10)", strconvPkg, convertTo(imports, value, from, types.Typ[types.Uint64]))
+	default:
+		return fmt.Sprintf("%s.FormatInt(%s, 10)", strconvPkg, convertTo(imports, value, from, types.Typ[types.Int64]))
+	}
+}
+
+func mapParseInt(b *funcBuilder, in parse.BuiltinConversion, value string, base string, label errLabel) string {
+	strconvPkg := b.imports.add("strconv")
+	str := convertTo(b.imports, value, in.From, types.Typ[types.String])
+
+	if types.Identical(in.To, types.Typ[types.Int]) {
+		return b.callChecked(base, fmt.Sprintf("%s.Atoi(%s)", strconvPkg, str), label)
+	}
+
+	// Parse as the widest type, with the bit size of the target...
+	parseFunc, parsed := "ParseInt", types.Typ[types.Int64]
+	if isUnsigned(in.To) {
+		parseFunc, parsed = "ParseUint", types.Typ[types.Uint64]
+	}
+
+	call := fmt.Sprintf("%s.%s(%s, 10, %d)", strconvPkg, parseFunc, str, bitSize(in.To))
+
+	// ...and then convert to it.
+	return convertTo(b.imports, b.callChecked(base, call, label), parsed, in.To)
+}
+
+// Render a Go conversion of value to the type to, unless it already is one.
+func convertTo(imports *imports, value string, from types.Type, to types.Type) string {
+	if types.Identical(from, to) {
+		return value
+	}
+
+	return mapTypeConversion(imports, parse.TypeConversion{Type: to}, value)
+}
+
+func isUnsigned(typ types.Type) bool {
+	basic, ok := typ.Underlying().(*types.Basic)
+	return ok && basic.Info()&types.IsUnsigned != 0
+}
+
+// The bitSize argument for strconv, where 0 means the size of int.
+func bitSize(typ types.Type) int {
+	basic, _ := typ.Underlying().(*types.Basic)
+
+	switch basic.Kind() {
+	case types.Int8, types.Uint8:
+		return 8
+	case types.Int16, types.Uint16:
+		return 16
+	case types.Int32, types.Uint32:
+		return 32
+	case types.Int64, types.Uint64:
+		return 64
+	}
+
+	return 0
+}
+
+// Pointers are only dereferenced if they are set.
+func mapDerefConversion(b *funcBuilder, in parse.DerefConversion, value string, base string, label errLabel) string {
+	name := b.names.fresh(base)
+	guarded := b.nested()
+	guarded.addStmt("%s = %s", name, mapConversion(guarded, in.Elem, "*"+value, base, label))
+
+	b.addStmt("var %s %s\nif %s != nil {\n%s\n}",
+		name, b.imports.typeString(in.Type, ""), value, strings.Join(guarded.stmts, "\n"))
+
+	return name
+}
+
+// The converted value is copied into a local (unless it is one already),
+// so that the result doesn't share memory with the source.
+func mapAddressConversion(b *funcBuilder, in parse.AddressConversion, value string, base string, label errLabel) string {
+	converted := mapConversion(b, in.Elem, value, base, label)
+	if converted != value && token.IsIdentifier(converted) {
+		return "&" + converted
+	}
+
+	name := b.names.fresh(base)
+	b.addStmt("%s := %s", name, converted)
+
+	return "&" + name
+}
@@ -0,0 +1,141 @@
+package template
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/liampulles/juryrig/internal/parse"
+)
+
+// Render the conversion of value, adding any statements it needs to b.
+// Locals are named after base, and errors are wrapped with label.
+func mapConversion(b *funcBuilder, in parse.Conversion, value string, base string, label errLabel) string {
+	switch v := in.(type) {
+	case nil:
+		return value
+	case parse.MethodConversion:
+		call := fmt.Sprintf("%s.%s(%s)", b.receiver, v.Method, value)
+		if v.ReturnsError {
+			return b.callChecked(base, call, label)
+		}
+
+		return call
+	case parse.TypeConversion:
+		return mapTypeConversion(b.imports, v, value)
+	case parse.BuiltinConversion:
+		return mapBuiltinConversion(b, v, value, base, label)
+	case parse.DerefConversion:
+		return mapDerefConversion(b, v, value, base, label)
+	case parse.AddressConversion:
+		return mapAddressConversion(b, v, value, base, label)
+	case parse.EnumConversion:
+		return mapEnumConversion(b, v, value, base, label)
+	case parse.SliceConversion:
+		return mapSliceConversion(b, v, value, base, label)
+	case parse.MapConversion:
+		return mapMapConversion(b, v, value, base, label)
+	}
+	// Should be handled by parse stage...
+	return fmt.Sprintf("<<ERROR: UNKNOWN CONVERSION TYPE %T>>", in)
+}
+
+func mapTypeConversion(imports *imports, in parse.TypeConversion, value string) string {
+	typ := imports.typeString(in.Type, "")
+	if strings.HasPrefix(typ, "*") || strings.HasPrefix(typ, "<-") || strings.HasPrefix(typ, "func") {
+		// (otherwise the conversion would parse as something else)
+		typ = fmt.Sprintf("(%s)", typ)
+	}
+
+	return fmt.Sprintf("%s(%s)", typ, value)
+}
+
+func mapSliceConversion(b *funcBuilder, in parse.SliceConversion, value string, base string, label errLabel) string {
+	name := b.names.fresh(base)
+	index := "_"
+
+	if conversionReturnsError(in.Elem) {
+		index = b.names.fresh("i")
+	}
+
+	elem := b.names.fresh("elem")
+
+	// Convert each element in the loop body...
+	loop := b.nested()
+	converted := mapConversion(loop, in.Elem, elem, base+"Elem", label.index(index))
+	loop.addStmt("%s = append(%s, %s)", name, name, converted)
+
+	// ...into a preallocated slice.
+	b.addStmt("var %s %s\nif %s != nil {\n%s = make(%s, 0, len(%s))\nfor %s, %s := range %s {\n%s\n}\n}",
+		name, b.imports.typeString(in.Type, ""), value,
+		name, b.imports.typeString(in.Type, ""), value,
+		index, elem, value,
+		strings.Join(loop.stmts, "\n"))
+
+	return name
+}
+
+func mapMapConversion(b *funcBuilder, in parse.MapConversion, value string, base string, label errLabel) string {
+	name := b.names.fresh(base)
+	key := b.names.fresh("key")
+	elem := b.names.fresh("elem")
+
+	// Convert each entry in the loop body...
+	loop := b.nested()
+	convertedKey := mapConversion(loop, in.Key, key, base+"Key", label.key(key))
+	convertedElem := mapConversion(loop, in.Elem, elem, base+"Elem", label.key(key))
+	loop.addStmt("%s[%s] = %s", name, convertedKey, convertedElem)
+
+	// ...into a presized map.
+	b.addStmt("var %s %s\nif %s != nil {\n%s = make(%s, len(%s))\nfor %s, %s := range %s {\n%s\n}\n}",
+		name, b.imports.typeString(in.Type, ""), value,
+		name, b.imports.typeString(in.Type, ""), value,
+		key, elem, value,
+		strings.Join(loop.stmts, "\n"))
+
+	return name
+}
+
+func conversionReturnsError(in parse.Conversion) bool {
+	switch v := in.(type) {
+	case parse.MethodConversion:
+		return v.ReturnsError
+	case parse.BuiltinConversion:
+		return v.ReturnsError
+	case parse.SliceConversion:
+		return conversionReturnsError(v.Elem)
+	case parse.MapConversion:
+		return conversionReturnsError(v.Key) || conversionReturnsError(v.Elem)
+	case parse.DerefConversion:
+		return conversionReturnsError(v.Elem)
+	case parse.AddressConversion:
+		return conversionReturnsError(v.Elem)
+	case parse.EnumConversion:
+		return v.Default == ""
+	}
+
+	return false
+}
+
+func mapEnumConversion(b *funcBuilder, in parse.EnumConversion, value string, base string, label errLabel) string {
+	b.imports.use(in.Imports)
+
+	name := b.names.fresh(base)
+
+	cases := make([]string, 0, len(in.Cases)+1)
+	for _, enumCase := range in.Cases {
+		cases = append(cases, fmt.Sprintf("case %s:\n%s = %s", enumCase.Source, name, enumCase.Target))
+	}
+
+	if in.Default != "" {
+		cases = append(cases, fmt.Sprintf("default:\n%s = %s", name, in.Default))
+	} else {
+		unknown := fmt.Sprintf(`%s.Errorf("%sunknown %s: %%v", %s)`, b.imports.add("fmt"),
+			label.prefix(), b.imports.typeString(in.From, ""), strings.Join(append(append([]string{}, label.args...), value), ", "))
+		cases = append(cases, fmt.Sprintf("default:\n%s", b.returnError(unknown)))
+	}
+
+	b.addStmt("var %s %s\nswitch %s {\n%s\n}",
+		name, b.imports.typeString(in.To, ""), value, strings.Join(cases, "\n"))
+
+	return name
+}
@@ -0,0 +1,76 @@
+package template
+
+import (
+	"go/types"
+	"strings"
+
+	"github.com/liampulles/juryrig/internal/parse"
+)
+
+// fieldNode is a field of a composite literal. It is either set by a
+// directive, or built from nested fields.
+type fieldNode struct {
+	name      string
+	directive parse.Directive
+	nested    []*fieldNode
+}
+
+func (n *fieldNode) insert(directive parse.Directive) {
+	target, ok := parse.DirectiveTarget(directive)
+	if !ok {
+		return
+	}
+
+	node := n
+	for _, name := range strings.Split(target.Field, ".") {
+		node = node.child(name)
+	}
+
+	node.directive = directive
+}
+
+func (n *fieldNode) child(name string) *fieldNode {
+	for _, node := range n.nested {
+		if node.name == name {
+			return node
+		}
+	}
+
+	node := &fieldNode{name: name} //nolint:exhaustruct
+	n.nested = append(n.nested, node)
+
+	return node
+}
+
+// Note: result is nillable.
+func fieldType(typ types.Type, name string) types.Type {
+	if ptr, ok := typ.Underlying().(*types.Pointer); ok {
+		typ = ptr.Elem()
+	}
+
+	structType, ok := typ.Underlying().(*types.Struct)
+	if !ok {
+		return nil
+	}
+
+	for i := 0; i < structType.NumFields(); i++ {
+		if field := structType.Field(i); field.Name() == name {
+			return field.Type()
+		}
+	}
+
+	return nil
+}
+
+// Note: result is nillable.
+func pathType(typ types.Type, path string) types.Type {
+	for _, name := range strings.Split(path, ".") {
+		if typ == nil {
+			return nil
+		}
+
+		typ = fieldType(typ, name)
+	}
+
+	return typ
+}
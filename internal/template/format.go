@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"go/format"
+	"go/types"
 	"strings"
 	"text/template"
 
@@ -12,13 +13,15 @@ import (
 
 //nolint:gochecknoglobals
 var mapperFileTemplate = template.Must(template.New("mapper-file").
-	Parse(`package {{ .Package }}{{ range $m, $mapper := .Mappers }}
-type {{$mapper.Name}}Impl struct{}{{ range $f, $func := $mapper.Functions }}
-func (impl *{{$mapper.Name}}Impl) {{$func.Name}}({{$func.Params}}) {{$func.Result}} {
-	return {{$func.Result}}{
-		{{ range $func.Directives }}{{.}},
-		{{ end }}
-	}
+	Parse(`package {{ .Package }}
+{{ if .Imports }}
+import ({{ range .Imports }}
+	{{ . }}{{ end }}
+)
+{{ end }}{{ range $m, $mapper := .Mappers }}
+type {{$mapper.ImplName}} struct{}{{ range $f, $func := $mapper.Functions }}
+func ({{$mapper.Receiver}} *{{$mapper.ImplName}}) {{$func.Name}}({{$func.Params}}) {{$func.Result}} {
+	{{ $func.Body }}
 }
 {{ end }}{{ end }}`))
 
@@ -41,76 +44,472 @@ func Generate(in parse.JuryrigSpec) []byte {
 }
 
 func mapSpec(in parse.JuryrigSpec) spec {
+	imports := newImports(in.PackageTypes)
+
+	// Register the packages used by copied code up front, so that types are
+	// rendered with the same names.
+	for _, mapper := range in.Mappers {
+		for _, fn := range mapper.MapperFunctions {
+			imports.use(copiedImports(fn))
+		}
+	}
+
 	mappers := make([]mapper, len(in.Mappers))
 	for i, mapper := range in.Mappers {
-		mappers[i] = mapMapper(mapper)
+		mappers[i] = mapMapper(imports, mapper)
 	}
 
 	return spec{
 		Package: in.Package,
+		Imports: imports.specs(),
 		Mappers: mappers,
 	}
 }
 
-func mapMapper(in parse.Mapper) mapper {
+func mapMapper(imports *imports, in parse.Mapper) mapper {
 	funcs := make([]function, len(in.MapperFunctions))
 	for i, fn := range in.MapperFunctions {
-		funcs[i] = mapFunc(fn)
+		funcs[i] = mapFunc(imports, in.Receiver, fn)
 	}
 
 	return mapper{
-		Name:      in.Name,
+		ImplName:  in.ImplName,
+		Receiver:  in.Receiver,
 		Functions: funcs,
 	}
 }
 
-func mapFunc(in parse.MapperFunction) function {
+func mapFunc(imports *imports, receiver string, in parse.MapperFunction) function {
 	params := make([]string, len(in.Function.Parameters))
 	for i, param := range in.Function.Parameters {
-		params[i] = mapParam(param)
+		params[i] = mapParam(imports, param)
+	}
+
+	// Directives sharing a target prefix are grouped into nested literals.
+	root := &fieldNode{} //nolint:exhaustruct
+	for _, directive := range in.Directives {
+		root.insert(directive)
+	}
+
+	b := newFuncBuilder(imports, receiver, in)
+
+	if in.Conversion != nil {
+		// Convert the parameter as a whole
+		param := in.Function.Parameters[0].Name
+		result := mapConversion(b, in.Conversion, param, "result", errLabel{}) //nolint:exhaustruct
+
+		return function{
+			Name:   in.Function.Name,
+			Params: strings.Join(params, ", "),
+			Result: mapResult(imports, in.Function),
+			Body:   b.body(result),
+		}
 	}
 
-	directives := make([]string, len(in.Directives))
-	for i, directive := range in.Directives {
-		directives[i] = mapDirective(directive)
+	if in.Options.ReturnNil {
+		b.addStmt("if %s {\n%s\n}", mapAllNil(in.Function.Parameters), b.returnResult("nil"))
+	}
+
+	if in.Function.UpdateTarget != "" {
+		mapUpdate(b, in.Options.NullValues, root.nested)
+
+		return function{
+			Name:   in.Function.Name,
+			Params: strings.Join(params, ", "),
+			Result: mapResult(imports, in.Function),
+			Body:   b.body(""),
+		}
 	}
 
+	literal := mapLiteral(b, in.Function.ResolvedResult, root.nested)
+
 	return function{
-		Name:       in.Function.Name,
-		Params:     strings.Join(params, ", "),
-		Result:     in.Function.Result,
-		Directives: directives,
+		Name:   in.Function.Name,
+		Params: strings.Join(params, ", "),
+		Result: mapResult(imports, in.Function),
+		Body:   b.body(literal),
 	}
 }
 
-func mapParam(in parse.Parameter) string {
-	return fmt.Sprintf("%s %s", in.Name, in.Type)
+func mapResult(imports *imports, in parse.Function) string {
+	if in.UpdateTarget != "" {
+		if in.ReturnsError {
+			return "error"
+		}
+
+		return ""
+	}
+
+	result := imports.typeString(in.ResolvedResult, in.Result)
+	if in.ReturnsError {
+		return fmt.Sprintf("(%s, error)", result)
+	}
+
+	return result
+}
+
+// A condition for when all pointer parameters are nil.
+func mapAllNil(params []parse.Parameter) string {
+	var conditions []string
+
+	for _, param := range params {
+		if _, ok := param.ResolvedType.Underlying().(*types.Pointer); ok {
+			conditions = append(conditions, fmt.Sprintf("%s == nil", param.Name))
+		}
+	}
+
+	return strings.Join(conditions, " && ")
+}
+
+func mapParam(imports *imports, in parse.Parameter) string {
+	return fmt.Sprintf("%s %s", in.Name, imports.typeString(in.ResolvedType, in.Type))
+}
+
+func mapFieldNode(b *funcBuilder, parentType types.Type, in *fieldNode) string {
+	if in.directive != nil {
+		value, ok := mapDirective(b, in.directive)
+		if !ok {
+			return fmt.Sprintf("// %s", formatField(in.name, value))
+		}
+
+		return formatField(in.name, value)
+	}
+
+	// Build the nested literal
+	typ := fieldType(parentType, in.name)
+
+	return formatField(in.name, mapLiteral(b, typ, in.nested))
+}
+
+func mapLiteral(b *funcBuilder, typ types.Type, fieldNodes []*fieldNode) string {
+	fields := make([]string, len(fieldNodes))
+	for i, node := range fieldNodes {
+		fields[i] = mapFieldNode(b, typ, node) + ",\n"
+	}
+
+	return fmt.Sprintf("%s{\n%s}", mapLiteralType(b.imports, typ), strings.Join(fields, ""))
 }
 
-func mapDirective(in parse.Directive) string {
+// The value of a field set by a directive, or false if it is ignored.
+func mapDirective(b *funcBuilder, in parse.Directive) (string, bool) {
 	switch v := in.(type) {
 	case parse.LinkDirective:
-		return formatDirective(v.Target, mapSource(v.Source))
+		return mapLinkValue(b, v), true
 	case parse.LinkFuncDirective:
-		return formatDirective(v.Target, mapLinkFuncValue(v))
+		return mapLinkFunc(b, v), true
+	case parse.ConstDirective:
+		b.imports.use(v.Imports)
+		return v.Value, true
+	case parse.ExprDirective:
+		b.imports.use(v.Imports)
+		return v.Expression, true
 	case parse.IgnoreDirective:
-		return fmt.Sprintf("// %s", formatDirective(v.Target, "(ignored)"))
+		return "(ignored)", false
 	}
 	// Should be handled by parse stage...
-	return fmt.Sprintf("<<ERROR: UNKNOWN DIRECTIVE TYPE %T>>", in)
+	return fmt.Sprintf("<<ERROR: UNKNOWN DIRECTIVE TYPE %T>>", in), true
 }
 
-func formatDirective(target parse.Target, value string) string {
-	return fmt.Sprintf("%s: %s", target.Field, value)
+// Update the target field by field. Every value is worked out before any
+// is assigned, so that the target is left alone if one of them fails.
+func mapUpdate(b *funcBuilder, nullValues parse.NullValues, fieldNodes []*fieldNode) {
+	var assignments []string
+
+	// Nested pointers are allocated (if they aren't already) just before
+	// something is assigned within them. Allocations which always happen
+	// only need to be written once.
+	allocated := make(map[string]bool)
+
+	var walk func(path string, typ types.Type, fieldNodes []*fieldNode, allocs []string)
+	walk = func(path string, typ types.Type, fieldNodes []*fieldNode, allocs []string) {
+		for _, node := range fieldNodes {
+			field := path + "." + node.name
+
+			if node.directive == nil {
+				nestedType := fieldType(typ, node.name)
+
+				nestedAllocs := allocs
+				if ptr, ok := nestedType.(*types.Pointer); ok {
+					nestedAllocs = append(append([]string{}, allocs...), fmt.Sprintf("if %s == nil {\n%s = &%s{}\n}",
+						field, field, b.imports.typeString(ptr.Elem(), "")))
+				}
+
+				walk(field, nestedType, node.nested, nestedAllocs)
+
+				continue
+			}
+
+			condition, assignment, ok := mapAssignment(b, nullValues, node.directive, field)
+			if !ok {
+				assignments = append(assignments, assignment)
+				continue
+			}
+
+			var stmts []string
+
+			for _, alloc := range allocs {
+				if allocated[alloc] {
+					continue
+				}
+
+				stmts = append(stmts, alloc)
+
+				if condition == "" {
+					allocated[alloc] = true
+				}
+			}
+
+			stmts = append(stmts, assignment)
+
+			if condition == "" {
+				assignments = append(assignments, stmts...)
+			} else {
+				assignments = append(assignments, fmt.Sprintf("if %s {\n%s\n}", condition, strings.Join(stmts, "\n")))
+			}
+		}
+	}
+
+	walk(b.fn.UpdateTarget, b.fn.ResolvedResult, fieldNodes, nil)
+
+	b.addStmt("%s", strings.Join(assignments, "\n"))
 }
 
-func mapLinkFuncValue(in parse.LinkFuncDirective) string {
+// The assignment of a field, and the condition for making it (if any).
+// Returns false if the field is ignored, in which case the assignment is
+// just a comment.
+func mapAssignment(b *funcBuilder, nullValues parse.NullValues, in parse.Directive, field string) (string, string, bool) {
+	if nullValues == parse.NullValuesSkip {
+		if condition, value, ok := mapSkippable(b, in); ok {
+			return condition, fmt.Sprintf("%s = %s", field, value), true
+		}
+	}
+
+	value, ok := mapDirective(b, in)
+	if !ok {
+		return "", fmt.Sprintf("// %s: %s", field, value), false
+	}
+
+	return "", fmt.Sprintf("%s = %s", field, value), true
+}
+
+// With the skip strategy, a field is only assigned if its sources can be
+// reached (and for links, if the source is set). Returns the condition for
+// assigning it, and the value to assign, if there is anything to check.
+func mapSkippable(b *funcBuilder, in parse.Directive) (string, string, bool) {
+	switch v := in.(type) {
+	case parse.LinkDirective:
+		if v.Default == "" {
+			return mapSkippableLink(b, v)
+		}
+	case parse.LinkFuncDirective:
+		conditions := mapSourceNilChecks(v.Sources...)
+		if len(conditions) == 0 {
+			return "", "", false
+		}
+
+		base, label := localName(v.Target.Field), targetLabel(v.Target)
+		condition, value := mapGuardedValue(b, conditions, v.Target, func(guarded *funcBuilder) string {
+			return mapLinkFuncCall(guarded, v, base, label)
+		})
+
+		return condition, value, true
+	}
+
+	return "", "", false
+}
+
+func mapSkippableLink(b *funcBuilder, in parse.LinkDirective) (string, string, bool) {
+	conditions := mapSourceNilChecks(in.Source)
+	conversion, source := in.Conversion, mapSource(in.Source)
+
+	if isSet, ok := mapIsSet(b.imports, in.Source); ok {
+		conditions = append(conditions, isSet)
+
+		// The pointer is known to be set, so needn't be checked again.
+		if deref, ok := conversion.(parse.DerefConversion); ok {
+			conversion, source = deref.Elem, "*"+source
+		}
+	}
+
+	if len(conditions) == 0 {
+		return "", "", false
+	}
+
+	base, label := localName(in.Target.Field), targetLabel(in.Target)
+	condition, value := mapGuardedValue(b, conditions, in.Target, func(guarded *funcBuilder) string {
+		return mapConversion(guarded, conversion, source, base, label)
+	})
+
+	return condition, value, true
+}
+
+// Work out a value which is only needed when the conditions hold. If that
+// takes statements, they are run up front (in case they fail) into a local.
+func mapGuardedValue(
+	b *funcBuilder,
+	conditions []string,
+	target parse.Target,
+	value func(guarded *funcBuilder) string,
+) (string, string) {
+	condition := strings.Join(conditions, " && ")
+
+	// (the name is only needed if the value takes statements)
+	name := b.names.fresh(localName(target.Field))
+	guarded := b.nested()
+	result := value(guarded)
+
+	if len(guarded.stmts) == 0 {
+		return condition, result
+	}
+
+	guarded.addStmt("%s = %s", name, result)
+	b.addStmt("var %s %s\nif %s {\n%s\n}",
+		name, b.imports.typeString(pathType(b.fn.ResolvedResult, target.Field), ""),
+		condition, strings.Join(guarded.stmts, "\n"))
+
+	return condition, name
+}
+
+// Conditions for each pointer along the source paths being set.
+func mapSourceNilChecks(sources ...parse.Source) []string {
+	var paths []string
+
+	seen := make(map[string]bool)
+
+	for _, source := range sources {
+		for _, path := range source.NilChecks {
+			if !seen[path] {
+				seen[path] = true
+
+				paths = append(paths, path)
+			}
+		}
+	}
+
+	return mapNonNil(paths)
+}
+
+// A condition for the source being neither nil nor the zero value, if it
+// can be compared.
+func mapIsSet(imports *imports, source parse.Source) (string, bool) {
+	switch {
+	case canBeNil(source.Type):
+		return fmt.Sprintf("%s != nil", mapSource(source)), true
+	case types.Comparable(source.Type):
+		return mapNonZero(imports, source), true
+	}
+
+	return "", false
+}
+
+// Types whose values may be nil, so that nil means the source isn't set.
+func canBeNil(typ types.Type) bool {
+	switch typ.Underlying().(type) {
+	case *types.Pointer, *types.Slice, *types.Map, *types.Chan, *types.Signature, *types.Interface:
+		return true
+	}
+
+	return false
+}
+
+func mapLinkValue(b *funcBuilder, in parse.LinkDirective) string {
+	base, label := localName(in.Target.Field), targetLabel(in.Target)
+
+	var conditions []string
+	if !in.AssumeNonNil {
+		conditions = mapNonNil(in.Source.NilChecks)
+	}
+
+	if in.Default != "" {
+		conditions = append(conditions, mapNonZero(b.imports, in.Source))
+	}
+
+	if len(conditions) == 0 {
+		return mapConversion(b, in.Conversion, mapSource(in.Source), base, label)
+	}
+
+	// Leave the target as the zero value (or the default) if the source
+	// can't be reached.
+	name := b.names.fresh(base)
+	guarded := b.nested()
+	guarded.addStmt("%s = %s", name,
+		mapConversion(guarded, in.Conversion, mapSource(in.Source), base, label))
+
+	fallback := ""
+	if in.Default != "" {
+		b.imports.use(in.DefaultImports)
+		fallback = fmt.Sprintf(" else {\n%s = %s\n}", name, in.Default)
+	}
+
+	b.addStmt("var %s %s\nif %s {\n%s\n}%s",
+		name, b.imports.typeString(pathType(b.fn.ResolvedResult, in.Target.Field), ""),
+		strings.Join(conditions, " && "), strings.Join(guarded.stmts, "\n"), fallback)
+
+	return name
+}
+
+func mapNonNil(paths []string) []string {
+	conditions := make([]string, len(paths))
+	for i, path := range paths {
+		conditions[i] = fmt.Sprintf("%s != nil", path)
+	}
+
+	return conditions
+}
+
+func mapNonZero(imports *imports, source parse.Source) string {
+	zero := zeroValue(imports, source.Type)
+	if strings.HasSuffix(zero, "}") {
+		// Composite literals need parentheses within an if statement.
+		zero = fmt.Sprintf("(%s)", zero)
+	}
+
+	return fmt.Sprintf("%s != %s", mapSource(source), zero)
+}
+
+func formatField(field string, value string) string {
+	return fmt.Sprintf("%s: %s", field, value)
+}
+
+func mapLinkFunc(b *funcBuilder, in parse.LinkFuncDirective) string {
+	base, label := localName(in.Target.Field), targetLabel(in.Target)
+
+	var conditions []string
+	if !in.AssumeNonNil {
+		conditions = mapSourceNilChecks(in.Sources...)
+	}
+
+	if len(conditions) == 0 {
+		return mapLinkFuncCall(b, in, base, label)
+	}
+
+	// Leave the target as the zero value if a source can't be reached.
+	name := b.names.fresh(base)
+	guarded := b.nested()
+	guarded.addStmt("%s = %s", name, mapLinkFuncCall(guarded, in, base, label))
+
+	b.addStmt("var %s %s\nif %s {\n%s\n}",
+		name, b.imports.typeString(pathType(b.fn.ResolvedResult, in.Target.Field), ""),
+		strings.Join(conditions, " && "), strings.Join(guarded.stmts, "\n"))
+
+	return name
+}
+
+func mapLinkFuncCall(b *funcBuilder, in parse.LinkFuncDirective, base string, label errLabel) string {
+	if in.ReturnsError {
+		return b.callChecked(base, mapLinkFuncValue(b.receiver, in), label)
+	}
+
+	return mapLinkFuncValue(b.receiver, in)
+}
+
+func mapLinkFuncValue(receiver string, in parse.LinkFuncDirective) string {
 	sources := make([]string, len(in.Sources))
 	for i, source := range in.Sources {
 		sources[i] = mapSource(source)
 	}
 
-	return fmt.Sprintf("impl.%s(%s)", in.FunctionName, strings.Join(sources, ", "))
+	return fmt.Sprintf("%s.%s(%s)", receiver, in.FunctionName, strings.Join(sources, ", "))
 }
 
 func mapSource(in parse.Source) string {
@@ -121,19 +520,30 @@ func mapSource(in parse.Source) string {
 	return in.Parameter
 }
 
+// Composite literals of pointer types are written as &T{...}.
+func mapLiteralType(imports *imports, typ types.Type) string {
+	if ptr, ok := typ.Underlying().(*types.Pointer); ok {
+		return "&" + imports.typeString(ptr.Elem(), "")
+	}
+
+	return imports.typeString(typ, "")
+}
+
 type spec struct {
 	Package string
+	Imports []string
 	Mappers []mapper
 }
 
 type mapper struct {
-	Name      string
+	ImplName  string
+	Receiver  string
 	Functions []function
 }
 
 type function struct {
-	Name       string
-	Params     string
-	Result     string
-	Directives []string
+	Name   string
+	Params string
+	Result string
+	Body   string
 }
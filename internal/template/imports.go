@@ -0,0 +1,101 @@
+package template
+
+import (
+	"fmt"
+	"go/types"
+	"path"
+	"sort"
+
+	"github.com/liampulles/juryrig/internal/parse"
+)
+
+// imports tracks the packages which the generated code refers to.
+type imports struct {
+	local *types.Package
+	names map[string]string
+}
+
+func newImports(local *types.Package) *imports {
+	return &imports{
+		local: local,
+		names: make(map[string]string),
+	}
+}
+
+// Render a type as it would be written in the local package. Falls back
+// to the raw string if the type is not known.
+func (i *imports) typeString(typ types.Type, raw string) string {
+	if typ == nil {
+		return raw
+	}
+
+	return types.TypeString(typ, i.qualifier)
+}
+
+func (i *imports) qualifier(pkg *types.Package) string {
+	if pkg == i.local {
+		return ""
+	}
+
+	// Code copied from the mapper file fixes the name of the packages it
+	// uses.
+	if name, ok := i.names[pkg.Path()]; ok {
+		return name
+	}
+
+	i.names[pkg.Path()] = pkg.Name()
+
+	return pkg.Name()
+}
+
+// Import a package by path, returning the name to refer to it by.
+func (i *imports) add(pkgPath string) string {
+	name := path.Base(pkgPath)
+	i.names[pkgPath] = name
+
+	return name
+}
+
+// Import packages as they are referred to by code copied from the mapper
+// file.
+func (i *imports) use(pkgs []parse.Import) {
+	for _, pkg := range pkgs {
+		i.names[pkg.Path] = pkg.Name
+	}
+}
+
+// The names of packages which the generated code may refer to, which
+// should not be shadowed.
+func (i *imports) packageNames() []string {
+	result := parse.PackageNames(i.local)
+	for _, name := range i.names {
+		result = append(result, name)
+	}
+
+	return result
+}
+
+// Note: result is nillable.
+func (i *imports) localScope() *types.Scope {
+	if i.local == nil {
+		return nil
+	}
+
+	return i.local.Scope()
+}
+
+func (i *imports) specs() []string {
+	result := make([]string, 0, len(i.names))
+
+	for pkgPath, name := range i.names {
+		if path.Base(pkgPath) == name {
+			result = append(result, fmt.Sprintf("%q", pkgPath))
+		} else {
+			result = append(result, fmt.Sprintf("%s %q", name, pkgPath))
+		}
+	}
+
+	sort.Strings(result)
+
+	return result
+}
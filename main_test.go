@@ -1,7 +1,9 @@
 package main_test
 
 import (
+	"io"
 	"os"
+	"path"
 	"testing"
 
 	goConfig "github.com/liampulles/go-config"
@@ -29,3 +31,276 @@ func TestJuryrig_ValidExample(t *testing.T) {
 	assert.NoError(t, err, "could not read actual")
 	assert.Equal(t, string(expected), string(actual))
 }
+
+func TestJuryrig_NestedSourcePaths(t *testing.T) {
+	assertGeneratesExpected(t, "testdata/nestedsource")
+}
+
+func TestJuryrig_NestedTargetPaths(t *testing.T) {
+	assertGeneratesExpected(t, "testdata/nestedtarget")
+}
+
+func TestJuryrig_PointerResults(t *testing.T) {
+	assertGeneratesExpected(t, "testdata/pointerresult")
+}
+
+func TestJuryrig_ErrorResults(t *testing.T) {
+	assertGeneratesExpected(t, "testdata/errorresult")
+}
+
+func TestJuryrig_LocalsDontShadowPackageTypes(t *testing.T) {
+	assertGeneratesExpected(t, "testdata/shadowtype")
+}
+
+func TestJuryrig_SliceMapping(t *testing.T) {
+	assertGeneratesExpected(t, "testdata/slices")
+}
+
+func TestJuryrig_MapMapping(t *testing.T) {
+	assertGeneratesExpected(t, "testdata/maps")
+}
+
+func TestJuryrig_NilSafeSources(t *testing.T) {
+	assertGeneratesExpected(t, "testdata/nilsafe")
+}
+
+func TestJuryrig_NilSafeEmbeddedSources(t *testing.T) {
+	assertGeneratesExpected(t, "testdata/embeddedsource")
+}
+
+func TestJuryrig_ConstValues(t *testing.T) {
+	assertGeneratesExpected(t, "testdata/const")
+}
+
+func TestJuryrig_ConstOfWrongType(t *testing.T) {
+	assertFailsToGenerate(t, "testdata/constmismatch",
+		`["three"] is not valid for target rating`)
+}
+
+func TestJuryrig_ExprValues(t *testing.T) {
+	assertGeneratesExpected(t, "testdata/expr")
+}
+
+func TestJuryrig_LocalsDontShadowExprPackages(t *testing.T) {
+	assertGeneratesExpected(t, "testdata/exprshadow")
+}
+
+func TestJuryrig_DefaultValues(t *testing.T) {
+	assertGeneratesExpected(t, "testdata/defaults")
+}
+
+func TestJuryrig_DefaultOfWrongType(t *testing.T) {
+	assertFailsToGenerate(t, "testdata/defaultmismatch",
+		"invalid default: [0] is not valid for target title")
+}
+
+func TestJuryrig_TypeConversions(t *testing.T) {
+	assertGeneratesExpected(t, "testdata/typeconv")
+}
+
+func TestJuryrig_NarrowingConversion(t *testing.T) {
+	assertFailsToGenerate(t, "testdata/narrowing",
+		"converting int64 to int32 may lose information")
+}
+
+func TestJuryrig_BuiltinConversions(t *testing.T) {
+	assertGeneratesExpected(t, "testdata/builtins")
+}
+
+func TestJuryrig_FailingBuiltinWithoutError(t *testing.T) {
+	assertFailsToGenerate(t, "testdata/builtinerror",
+		"converting string to int can fail, so ToInternalFilm must return an error")
+}
+
+func TestJuryrig_EnumMapping(t *testing.T) {
+	assertGeneratesExpected(t, "testdata/enums")
+}
+
+func TestJuryrig_EnumWithoutDefault(t *testing.T) {
+	assertFailsToGenerate(t, "testdata/enumnodefault",
+		"ToStatus needs an enum default")
+}
+
+func TestJuryrig_EnumWithoutMethod(t *testing.T) {
+	assertFailsToGenerate(t, "testdata/enumcast",
+		"ExternalStatus and Status both have constants, so need a mapper method to convert between them")
+}
+
+func TestJuryrig_EnumMappingByName(t *testing.T) {
+	assertGeneratesExpected(t, "testdata/enumnames")
+}
+
+func TestJuryrig_UnmatchedEnumConstant(t *testing.T) {
+	assertFailsToGenerate(t, "testdata/enumunmatched",
+		"enum constants [ExternalStatusSuspended] have no match in Status")
+}
+
+func TestJuryrig_TagMatching(t *testing.T) {
+	assertGeneratesExpected(t, "testdata/tags")
+}
+
+func TestJuryrig_NormalizedNameMatching(t *testing.T) {
+	assertGeneratesExpected(t, "testdata/matching")
+}
+
+func TestJuryrig_AmbiguousNameMatch(t *testing.T) {
+	assertFailsToGenerate(t, "testdata/matchambiguous",
+		"target field Username of ToInternalUser could come from any of [eu.UserName, eu.user_name]")
+}
+
+func TestJuryrig_UpdateInPlace(t *testing.T) {
+	assertGeneratesExpected(t, "testdata/update")
+}
+
+func TestJuryrig_SkipNullValues(t *testing.T) {
+	assertGeneratesExpected(t, "testdata/patch")
+}
+
+func TestJuryrig_NullValuesWithResult(t *testing.T) {
+	assertFailsToGenerate(t, "testdata/nullvaluesresult",
+		"nullvalues only applies to methods which update a parameter")
+}
+
+func TestJuryrig_InverseMapping(t *testing.T) {
+	assertGeneratesExpected(t, "testdata/inverse")
+}
+
+func TestJuryrig_InverseOfExpression(t *testing.T) {
+	assertFailsToGenerate(t, "testdata/inverseexpr",
+		"it is an expression, so [Nickname] must be set by directives instead")
+}
+
+func TestJuryrig_InheritDirectives(t *testing.T) {
+	assertGeneratesExpected(t, "testdata/inherit")
+}
+
+func TestJuryrig_InheritMissingField(t *testing.T) {
+	assertFailsToGenerate(t, "testdata/inheritmissing",
+		"type ExternalFilmV2 has no field Title")
+}
+
+func TestJuryrig_ImplAndReceiverNames(t *testing.T) {
+	assertGeneratesExpected(t, "testdata/implnames")
+}
+
+func TestJuryrig_ParameterNamedAsReceiver(t *testing.T) {
+	assertFailsToGenerate(t, "testdata/receiverclash",
+		"parameter m of ToInternalFilm has the same name as the receiver")
+}
+
+func TestJuryrig_ImplNameAlreadyDeclared(t *testing.T) {
+	assertFailsToGenerate(t, "testdata/implclash",
+		"InternalFilm is already declared in the package")
+}
+
+func TestJuryrig_ReceiverNamedAsPackage(t *testing.T) {
+	assertFailsToGenerate(t, "testdata/receiverpackage",
+		"receiver time has the same name as a package which generated code may use")
+}
+
+func TestJuryrig_ParameterNamedAsPackage(t *testing.T) {
+	assertFailsToGenerate(t, "testdata/parampackage",
+		"parameter time of ToEvent has the same name as a package which generated code may use")
+}
+
+func TestJuryrig_ImportAlias(t *testing.T) {
+	assertGeneratesExpected(t, "testdata/importalias")
+}
+
+func TestJuryrig_ImportAliasOfBuiltinPackage(t *testing.T) {
+	assertFailsToGenerate(t, "testdata/importaliasbuiltin",
+		"refers to package time as tm, but generated code refers to it as time")
+}
+
+func TestJuryrig_LinkToMissingField(t *testing.T) {
+	assertFailsToGenerate(t, "testdata/typo",
+		"type ExternalFilm has no field titel")
+}
+
+func TestJuryrig_ImplicitMapping(t *testing.T) {
+	assertGeneratesExpected(t, "testdata/implicit")
+}
+
+func TestJuryrig_UnmappedTargetField(t *testing.T) {
+	assertFailsToGenerate(t, "testdata/unmapped",
+		"target fields [director] of ToInternalFilm are not mapped or ignored")
+}
+
+func TestJuryrig_UnusedSourceField(t *testing.T) {
+	assertFailsToGenerate(t, "testdata/unusedsource",
+		"source fields [eu.age] of ToInternalUser are not used")
+}
+
+func TestJuryrig_UnusedEmbeddedSourceField(t *testing.T) {
+	assertFailsToGenerate(t, "testdata/unusedembedded",
+		"source fields [eu.Audit] of ToInternalUser are not used")
+}
+
+func TestJuryrig_UnmappedTargetFieldWarning(t *testing.T) {
+	stderr := assertGeneratesExpected(t, "testdata/unmappedwarn")
+	assert.Contains(t, stderr, "WARNING: target fields [director] of ToInternalFilm are not mapped or ignored")
+}
+
+// Returns what was written to stderr.
+func assertGeneratesExpected(t *testing.T, dir string) string {
+	t.Helper()
+
+	// Setup expectations
+	expected, err := os.ReadFile(path.Join(dir, "expected.go"))
+	assert.NoError(t, err, "could not read expected")
+
+	// Exercise SUT
+	code, stderr := runGen(t, dir)
+
+	// Verify results
+	assert.Equal(t, 0, code, "non-zero exit code")
+	actual, err := os.ReadFile(path.Join(dir, "actual.go"))
+	assert.NoError(t, err, "could not read actual")
+	assert.Equal(t, string(expected), string(actual))
+
+	return stderr
+}
+
+func assertFailsToGenerate(t *testing.T, dir string, expectedErr string) {
+	t.Helper()
+
+	// Exercise SUT
+	code, stderr := runGen(t, dir)
+
+	// Verify results
+	assert.Equal(t, 1, code, "expected failure exit code")
+	assert.Contains(t, stderr, expectedErr)
+	assert.NoFileExists(t, path.Join(dir, "actual.go"))
+}
+
+// Run gen for the mapper in dir, capturing stderr.
+func runGen(t *testing.T, dir string) (int, string) {
+	t.Helper()
+
+	// Setup fixture
+	args := []string{"juryrig", "gen", "-o", "actual.go"}
+	cfgSource := goConfig.MapSource{
+		"GOFILE": path.Join(dir, "mapper.go"),
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("could not create pipe: %v", err)
+	}
+
+	stderr := os.Stderr
+	os.Stderr = w
+
+	defer func() {
+		os.Stderr = stderr
+	}()
+
+	code := wire.Run(args, cfgSource)
+
+	w.Close()
+
+	captured, err := io.ReadAll(r)
+	assert.NoError(t, err, "could not read stderr")
+
+	return code, string(captured)
+}
@@ -0,0 +1,9 @@
+package builtinerror
+
+//go:generate juryrig gen -o zz.mapper.impl.go
+
+// +juryrig:mapper
+type Mapper interface {
+	// +juryrig:link:ef.runtime->runtime
+	ToInternalFilm(ef ExternalFilm) InternalFilm
+}
@@ -0,0 +1,9 @@
+package builtinerror
+
+type ExternalFilm struct {
+	runtime string
+}
+
+type InternalFilm struct {
+	runtime int
+}
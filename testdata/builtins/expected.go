@@ -0,0 +1,83 @@
+package builtins
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+type MapperImpl struct{}
+
+func (impl *MapperImpl) ToInternalFilm(ef ExternalFilm) (InternalFilm, error) {
+	released, err := time.Parse("2006-01-02", ef.released)
+	if err != nil {
+		return InternalFilm{}, fmt.Errorf("released: %w", err)
+	}
+
+	views, err := strconv.ParseUint(ef.views, 10, 32)
+	if err != nil {
+		return InternalFilm{}, fmt.Errorf("views: %w", err)
+	}
+
+	var rating int
+	if ef.rating != nil {
+		rating = *ef.rating
+	}
+
+	director := ef.director
+
+	var updated string
+	if ef.updated != nil {
+		updated = (*ef.updated).Format(time.RFC3339)
+	}
+
+	return InternalFilm{
+		released: released,
+		runtime:  time.Duration(ef.runtime) * time.Minute,
+		script:   string(ef.script),
+		views:    uint32(views),
+		rating:   rating,
+		director: &director,
+		updated:  updated,
+	}, nil
+}
+
+func (impl *MapperImpl) ToExternalFilm(inf InternalFilm) (ExternalFilm, error) {
+	rating := inf.rating
+
+	var director string
+	if inf.director != nil {
+		director = *inf.director
+	}
+
+	updated, err := time.Parse(time.RFC3339, inf.updated)
+	if err != nil {
+		return ExternalFilm{}, fmt.Errorf("updated: %w", err)
+	}
+
+	return ExternalFilm{
+		released: inf.released.Format(time.RFC3339),
+		runtime:  int(inf.runtime / time.Second),
+		script:   []byte(inf.script),
+		views:    strconv.FormatUint(uint64(inf.views), 10),
+		rating:   &rating,
+		director: director,
+		updated:  &updated,
+	}, nil
+}
+
+func (impl *MapperImpl) ToTimes(stamps []string) ([]time.Time, error) {
+	var result []time.Time
+	if stamps != nil {
+		result = make([]time.Time, 0, len(stamps))
+		for i, elem := range stamps {
+			resultElem, err := time.Parse(time.RFC3339, elem)
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+			result = append(result, resultElem)
+		}
+	}
+
+	return result, nil
+}
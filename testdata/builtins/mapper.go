@@ -0,0 +1,14 @@
+package builtins
+
+import "time"
+
+//go:generate juryrig gen -o zz.mapper.impl.go
+
+// +juryrig:mapper
+type Mapper interface {
+	// +juryrig:link:ef.released->released,format="2006-01-02"
+	// +juryrig:link:ef.runtime->runtime,format=minutes
+	ToInternalFilm(ef ExternalFilm) (InternalFilm, error)
+	ToExternalFilm(inf InternalFilm) (ExternalFilm, error)
+	ToTimes(stamps []string) ([]time.Time, error)
+}
@@ -0,0 +1,23 @@
+package builtins
+
+import "time"
+
+type ExternalFilm struct {
+	released string
+	runtime  int
+	script   []byte
+	views    string
+	rating   *int
+	director string
+	updated  *time.Time
+}
+
+type InternalFilm struct {
+	released time.Time
+	runtime  time.Duration
+	script   string
+	views    uint32
+	rating   int
+	director *string
+	updated  string
+}
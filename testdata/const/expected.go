@@ -0,0 +1,18 @@
+package constant
+
+import (
+	"time"
+)
+
+type MapperImpl struct{}
+
+func (impl *MapperImpl) ToInternalFilm(ef ExternalFilm, released time.Time) InternalFilm {
+	return InternalFilm{
+		director: "unknown->none",
+		rating:   DefaultRating,
+		length:   90 * time.Minute,
+		genre:    GenreDrama,
+		released: released,
+		title:    ef.title,
+	}
+}
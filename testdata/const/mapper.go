@@ -0,0 +1,15 @@
+package constant
+
+import "time"
+
+//go:generate juryrig gen -o zz.mapper.impl.go
+
+// +juryrig:mapper
+type Mapper interface {
+	// +juryrig:const:"unknown->none"->director
+	// +juryrig:const:DefaultRating->rating
+	// +juryrig:const:90 * time.Minute->length
+	// +juryrig:const:GenreDrama->genre
+	// +juryrig:link:released->released
+	ToInternalFilm(ef ExternalFilm, released time.Time) InternalFilm
+}
@@ -0,0 +1,22 @@
+package constant
+
+import "time"
+
+const DefaultRating = 3
+
+type Genre string
+
+const GenreDrama Genre = "drama"
+
+type ExternalFilm struct {
+	title string
+}
+
+type InternalFilm struct {
+	title    string
+	director string
+	rating   int
+	length   time.Duration
+	genre    Genre
+	released time.Time
+}
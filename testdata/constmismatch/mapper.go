@@ -0,0 +1,9 @@
+package constmismatch
+
+//go:generate juryrig gen -o zz.mapper.impl.go
+
+// +juryrig:mapper
+type Mapper interface {
+	// +juryrig:const:"three"->rating
+	ToInternalFilm(ef ExternalFilm) InternalFilm
+}
@@ -0,0 +1,10 @@
+package constmismatch
+
+type ExternalFilm struct {
+	title string
+}
+
+type InternalFilm struct {
+	title  string
+	rating int
+}
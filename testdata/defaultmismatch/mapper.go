@@ -0,0 +1,10 @@
+package defaultmismatch
+
+//go:generate juryrig gen -o zz.mapper.impl.go
+
+// +juryrig:mapper
+type Mapper interface {
+	// +juryrig:link:ef.title->title,default=0
+	// +juryrig:link:ef.details.runtime->runtime,default=DefaultRuntime
+	ToInternalFilm(ef ExternalFilm) InternalFilm
+}
@@ -0,0 +1,17 @@
+package defaultmismatch
+
+const DefaultRuntime = 90
+
+type ExternalDetails struct {
+	runtime int
+}
+
+type ExternalFilm struct {
+	title   string
+	details *ExternalDetails
+}
+
+type InternalFilm struct {
+	title   string
+	runtime int
+}
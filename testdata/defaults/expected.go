@@ -0,0 +1,24 @@
+package defaults
+
+type MapperImpl struct{}
+
+func (impl *MapperImpl) ToInternalFilm(ef ExternalFilm) InternalFilm {
+	var title string
+	if ef.title != "" {
+		title = ef.title
+	} else {
+		title = "Untitled"
+	}
+
+	var runtime int
+	if ef.details != nil && ef.details.runtime != 0 {
+		runtime = ef.details.runtime
+	} else {
+		runtime = DefaultRuntime
+	}
+
+	return InternalFilm{
+		title:   title,
+		runtime: runtime,
+	}
+}
@@ -0,0 +1,10 @@
+package defaults
+
+//go:generate juryrig gen -o zz.mapper.impl.go
+
+// +juryrig:mapper
+type Mapper interface {
+	// +juryrig:link:ef.title->title,default="Untitled"
+	// +juryrig:link:ef.details.runtime->runtime,default=DefaultRuntime
+	ToInternalFilm(ef ExternalFilm) InternalFilm
+}
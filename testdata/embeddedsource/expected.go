@@ -0,0 +1,27 @@
+package embeddedsource
+
+type MapperImpl struct{}
+
+func (impl *MapperImpl) ToInternalUser(eu ExternalUser) InternalUser {
+	var contact string
+	if eu.Base != nil {
+		contact = eu.Email
+	}
+
+	var verified bool
+	if eu.Base != nil && eu.Account != nil {
+		verified = eu.Account.Verified
+	}
+
+	var name string
+	if eu.Base != nil {
+		name = eu.Name
+	}
+
+	return InternalUser{
+		Contact:  contact,
+		Verified: verified,
+		Name:     name,
+		Age:      eu.Age,
+	}
+}
@@ -0,0 +1,10 @@
+package embeddedsource
+
+//go:generate juryrig gen -o zz.mapper.impl.go
+
+// +juryrig:mapper
+type Mapper interface {
+	// +juryrig:link:eu.Email->Contact
+	// +juryrig:link:eu.Account.Verified->Verified
+	ToInternalUser(eu ExternalUser) InternalUser
+}
@@ -0,0 +1,23 @@
+package embeddedsource
+
+type ExternalUser struct {
+	*Base
+	Age int
+}
+
+type Base struct {
+	Name  string
+	Email string
+	*Account
+}
+
+type Account struct {
+	Verified bool
+}
+
+type InternalUser struct {
+	Name     string
+	Age      int
+	Contact  string
+	Verified bool
+}
@@ -0,0 +1,9 @@
+package enumcast
+
+//go:generate juryrig gen -o zz.mapper.impl.go
+
+// +juryrig:mapper
+type Mapper interface {
+	// +juryrig:link:eu.Status->Status
+	ToInternalUser(eu ExternalUser) InternalUser
+}
@@ -0,0 +1,26 @@
+package enumcast
+
+type ExternalStatus int
+
+const (
+	ExternalStatusActive ExternalStatus = iota
+	ExternalStatusDisabled
+)
+
+type Status int
+
+const (
+	StatusUnknown Status = iota
+	StatusActive
+	StatusDisabled
+)
+
+type ExternalUser struct {
+	Name   string
+	Status ExternalStatus
+}
+
+type InternalUser struct {
+	Name   string
+	Status Status
+}
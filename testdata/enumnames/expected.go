@@ -0,0 +1,37 @@
+package enumnames
+
+import (
+	"fmt"
+)
+
+type MapperImpl struct{}
+
+func (impl *MapperImpl) ToStatus(s ExternalStatus) Status {
+	var result Status
+	switch s {
+	case ExternalStatusActive:
+		result = StatusActive
+	case ExternalStatusInactive:
+		result = StatusInactive
+	default:
+		result = StatusUnknown
+	}
+
+	return result
+}
+
+func (impl *MapperImpl) ToRole(r ExternalRole) (Role, error) {
+	var result Role
+	switch r {
+	case ExtGuest:
+		result = Member
+	case ExtAdmin:
+		result = Admin
+	case ExtMember:
+		result = Member
+	default:
+		return "", fmt.Errorf("unknown ExternalRole: %v", r)
+	}
+
+	return result, nil
+}
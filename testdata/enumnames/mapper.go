@@ -0,0 +1,13 @@
+package enumnames
+
+//go:generate juryrig gen -o zz.mapper.impl.go
+
+// +juryrig:mapper
+type Mapper interface {
+	// +juryrig:enum:_->StatusUnknown
+	ToStatus(s ExternalStatus) Status
+	// +juryrig:enumprefix:Ext->
+	// +juryrig:enum:ExtGuest->Member
+	// +juryrig:enumerror
+	ToRole(r ExternalRole) (Role, error)
+}
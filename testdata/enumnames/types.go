@@ -0,0 +1,33 @@
+package enumnames
+
+type ExternalStatus string
+
+const (
+	ExternalStatusActive   ExternalStatus = "ACTIVE"
+	ExternalStatusInactive ExternalStatus = "INACTIVE"
+	// An alias, for backwards compatibility.
+	ExternalStatusDisabled ExternalStatus = "INACTIVE"
+)
+
+type Status int
+
+const (
+	StatusUnknown Status = iota
+	StatusActive
+	StatusInactive
+)
+
+type ExternalRole int
+
+const (
+	ExtAdmin ExternalRole = iota + 1
+	ExtMember
+	ExtGuest
+)
+
+type Role string
+
+const (
+	Admin  Role = "admin"
+	Member Role = "member"
+)
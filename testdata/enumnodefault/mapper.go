@@ -0,0 +1,9 @@
+package enumnodefault
+
+//go:generate juryrig gen -o zz.mapper.impl.go
+
+// +juryrig:mapper
+type Mapper interface {
+	// +juryrig:enum:ACTIVE->StatusActive
+	ToStatus(s string) Status
+}
@@ -0,0 +1,8 @@
+package enumnodefault
+
+type Status int
+
+const (
+	StatusUnknown Status = iota
+	StatusActive
+)
@@ -0,0 +1,47 @@
+package enums
+
+import (
+	"fmt"
+)
+
+type MapperImpl struct{}
+
+func (impl *MapperImpl) ToInternalUser(eu ExternalUser) (InternalUser, error) {
+	role, err := impl.ToRole(eu.role)
+	if err != nil {
+		return InternalUser{}, fmt.Errorf("role: %w", err)
+	}
+
+	return InternalUser{
+		status: impl.ToStatus(eu.status),
+		role:   role,
+	}, nil
+}
+
+func (impl *MapperImpl) ToStatus(s string) Status {
+	var result Status
+	switch s {
+	case "ACTIVE":
+		result = StatusActive
+	case "DISABLED":
+		result = StatusInactive
+	default:
+		result = StatusUnknown
+	}
+
+	return result
+}
+
+func (impl *MapperImpl) ToRole(r ExternalRole) (Role, error) {
+	var result Role
+	switch r {
+	case ExternalRoleAdmin:
+		result = RoleAdmin
+	case ExternalRoleMember:
+		result = RoleMember
+	default:
+		return 0, fmt.Errorf("unknown ExternalRole: %v", r)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,16 @@
+package enums
+
+//go:generate juryrig gen -o zz.mapper.impl.go
+
+// +juryrig:mapper
+type Mapper interface {
+	ToInternalUser(eu ExternalUser) (InternalUser, error)
+	// +juryrig:enum:ACTIVE->StatusActive
+	// +juryrig:enum:"DISABLED"->StatusInactive
+	// +juryrig:enum:_->StatusUnknown
+	ToStatus(s string) Status
+	// +juryrig:enum:ExternalRoleAdmin->RoleAdmin
+	// +juryrig:enum:ExternalRoleMember->RoleMember
+	// +juryrig:enumerror
+	ToRole(r ExternalRole) (Role, error)
+}
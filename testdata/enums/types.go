@@ -0,0 +1,33 @@
+package enums
+
+type Status int
+
+const (
+	StatusUnknown Status = iota
+	StatusActive
+	StatusInactive
+)
+
+type ExternalRole string
+
+const (
+	ExternalRoleAdmin  ExternalRole = "admin"
+	ExternalRoleMember ExternalRole = "member"
+)
+
+type Role int
+
+const (
+	RoleMember Role = iota
+	RoleAdmin
+)
+
+type ExternalUser struct {
+	status string
+	role   ExternalRole
+}
+
+type InternalUser struct {
+	status Status
+	role   Role
+}
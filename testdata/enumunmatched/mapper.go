@@ -0,0 +1,9 @@
+package enumunmatched
+
+//go:generate juryrig gen -o zz.mapper.impl.go
+
+// +juryrig:mapper
+type Mapper interface {
+	// +juryrig:enum:_->StatusUnknown
+	ToStatus(s ExternalStatus) Status
+}
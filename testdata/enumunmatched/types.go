@@ -0,0 +1,15 @@
+package enumunmatched
+
+type ExternalStatus string
+
+const (
+	ExternalStatusActive    ExternalStatus = "ACTIVE"
+	ExternalStatusSuspended ExternalStatus = "SUSPENDED"
+)
+
+type Status int
+
+const (
+	StatusUnknown Status = iota
+	StatusActive
+)
@@ -0,0 +1,37 @@
+package errorresult
+
+import (
+	"fmt"
+)
+
+type MapperImpl struct{}
+
+func (impl *MapperImpl) ToInternalUserFilm(ef ExternalFilm, eu ExternalUser) (InternalUserFilm, error) {
+	user, err := impl.ToInternalUser(eu)
+	if err != nil {
+		return InternalUserFilm{}, fmt.Errorf("user: %w", err)
+	}
+
+	owner, err := impl.ToInternalUserPtr(eu)
+	if err != nil {
+		return InternalUserFilm{}, fmt.Errorf("owner: %w", err)
+	}
+
+	return InternalUserFilm{
+		user:  user,
+		owner: owner,
+		title: ef.title,
+	}, nil
+}
+
+func (impl *MapperImpl) ToInternalUser(eu ExternalUser) (InternalUser, error) {
+	return InternalUser{
+		username: eu.username,
+	}, nil
+}
+
+func (impl *MapperImpl) ToInternalUserPtr(eu ExternalUser) (*InternalUser, error) {
+	return &InternalUser{
+		username: eu.username,
+	}, nil
+}
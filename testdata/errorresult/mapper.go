@@ -0,0 +1,12 @@
+package errorresult
+
+//go:generate juryrig gen -o zz.mapper.impl.go
+
+// +juryrig:mapper
+type Mapper interface {
+	// +juryrig:linkfunc:eu->ToInternalUser->user
+	// +juryrig:linkfunc:eu->ToInternalUserPtr->owner
+	ToInternalUserFilm(ef ExternalFilm, eu ExternalUser) (InternalUserFilm, error)
+	ToInternalUser(eu ExternalUser) (InternalUser, error)
+	ToInternalUserPtr(eu ExternalUser) (*InternalUser, error)
+}
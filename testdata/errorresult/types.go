@@ -0,0 +1,19 @@
+package errorresult
+
+type ExternalFilm struct {
+	title string
+}
+
+type ExternalUser struct {
+	username string
+}
+
+type InternalUser struct {
+	username string
+}
+
+type InternalUserFilm struct {
+	title string
+	user  InternalUser
+	owner *InternalUser
+}
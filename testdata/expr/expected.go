@@ -0,0 +1,17 @@
+package expr
+
+import (
+	"strings"
+)
+
+type MapperImpl struct{}
+
+func (impl *MapperImpl) ToInternalFilm(ef ExternalFilm, eu ExternalUser) InternalFilm {
+	return InternalFilm{
+		title:          strings.ToUpper(ef.title),
+		runtimeSeconds: ef.runtime * 60,
+		user: InternalUser{
+			name: eu.first + " " + eu.last,
+		},
+	}
+}
@@ -0,0 +1,12 @@
+package expr
+
+//go:generate juryrig gen -o zz.mapper.impl.go
+
+// +juryrig:mapper
+// +juryrig:unmappedsource:error
+type Mapper interface {
+	// +juryrig:expr:strings.ToUpper(ef.title)->title
+	// +juryrig:expr:ef.runtime * 60->runtimeSeconds
+	// +juryrig:expr:eu.first + " " + eu.last->user.name
+	ToInternalFilm(ef ExternalFilm, eu ExternalUser) InternalFilm
+}
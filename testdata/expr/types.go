@@ -0,0 +1,21 @@
+package expr
+
+type ExternalFilm struct {
+	title   string
+	runtime int
+}
+
+type ExternalUser struct {
+	first string
+	last  string
+}
+
+type InternalUser struct {
+	name string
+}
+
+type InternalFilm struct {
+	title          string
+	runtimeSeconds int
+	user           InternalUser
+}
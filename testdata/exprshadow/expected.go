@@ -0,0 +1,19 @@
+package exprshadow
+
+import (
+	"strings"
+)
+
+type MapperImpl struct{}
+
+func (impl *MapperImpl) ToInternalFilm(ef ExternalFilm) InternalFilm {
+	var strings2 string
+	if ef.Meta != nil {
+		strings2 = ef.Meta.Tag
+	}
+
+	return InternalFilm{
+		Strings: strings2,
+		Title:   strings.ToUpper(ef.Title),
+	}
+}
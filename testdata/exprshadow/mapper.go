@@ -0,0 +1,10 @@
+package exprshadow
+
+//go:generate juryrig gen -o zz.mapper.impl.go
+
+// +juryrig:mapper
+type Mapper interface {
+	// +juryrig:link:ef.Meta.Tag->Strings
+	// +juryrig:expr:strings.ToUpper(ef.Title)->Title
+	ToInternalFilm(ef ExternalFilm) InternalFilm
+}
@@ -0,0 +1,15 @@
+package exprshadow
+
+type ExternalFilm struct {
+	Title string
+	Meta  *Meta
+}
+
+type Meta struct {
+	Tag string
+}
+
+type InternalFilm struct {
+	Title   string
+	Strings string
+}
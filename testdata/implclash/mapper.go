@@ -0,0 +1,9 @@
+package implclash
+
+//go:generate juryrig gen -o zz.mapper.impl.go
+
+// +juryrig:mapper
+// +juryrig:impl:InternalFilm
+type Mapper interface {
+	ToInternalFilm(ef ExternalFilm) InternalFilm
+}
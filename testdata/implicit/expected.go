@@ -0,0 +1,13 @@
+package implicit
+
+type MapperImpl struct{}
+
+func (impl *MapperImpl) ToInternalFilm(ef ExternalFilm, eu ExternalUser) InternalFilm {
+	return InternalFilm{
+		director: ef.name,
+		// rating: (ignored),
+		title:    ef.title,
+		runtime:  ef.runtime,
+		username: eu.username,
+	}
+}
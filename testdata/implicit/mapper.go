@@ -0,0 +1,10 @@
+package implicit
+
+//go:generate juryrig gen -o zz.mapper.impl.go
+
+// +juryrig:mapper
+type Mapper interface {
+	// +juryrig:link:ef.name->director
+	// +juryrig:ignore:rating
+	ToInternalFilm(ef ExternalFilm, eu ExternalUser) InternalFilm
+}
@@ -0,0 +1,21 @@
+package implicit
+
+type ExternalFilm struct {
+	title   string
+	runtime int
+	name    string
+	rating  int
+}
+
+type ExternalUser struct {
+	username string
+	rating   int
+}
+
+type InternalFilm struct {
+	title    string
+	runtime  int
+	director string
+	username string
+	rating   int
+}
@@ -0,0 +1,25 @@
+package implnames
+
+type FilmMapper struct{}
+
+func (m *FilmMapper) ToInternalFilm(ef ExternalFilm) InternalFilm {
+	var cast []InternalPerson
+	if ef.Cast != nil {
+		cast = make([]InternalPerson, 0, len(ef.Cast))
+		for _, elem := range ef.Cast {
+			cast = append(cast, m.ToInternalPerson(elem))
+		}
+	}
+
+	return InternalFilm{
+		Director: m.ToInternalPerson(ef.Director),
+		Title:    ef.Title,
+		Cast:     cast,
+	}
+}
+
+func (m *FilmMapper) ToInternalPerson(ep ExternalPerson) InternalPerson {
+	return InternalPerson{
+		Name: ep.Name,
+	}
+}
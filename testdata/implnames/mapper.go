@@ -0,0 +1,13 @@
+package implnames
+
+//go:generate juryrig gen -o zz.mapper.impl.go
+
+// +juryrig:mapper
+// +juryrig:impl:FilmMapper
+// +juryrig:receiver:m
+// +juryrig:unmapped:ignore
+type Mapper interface {
+	// +juryrig:linkfunc:ef.Director->ToInternalPerson->Director
+	ToInternalFilm(ef ExternalFilm) InternalFilm
+	ToInternalPerson(ep ExternalPerson) InternalPerson
+}
@@ -0,0 +1,22 @@
+package implnames
+
+type ExternalFilm struct {
+	Title    string
+	Director ExternalPerson
+	Cast     []ExternalPerson
+}
+
+type ExternalPerson struct {
+	Name string
+}
+
+type InternalFilm struct {
+	Title    string
+	Director InternalPerson
+	Cast     []InternalPerson
+	Rating   int
+}
+
+type InternalPerson struct {
+	Name string
+}
@@ -0,0 +1,28 @@
+package importalias
+
+import (
+	ext "github.com/liampulles/juryrig/testdata/importalias/external"
+)
+
+type MapperImpl struct{}
+
+func (impl *MapperImpl) ToFilm(ef ext.Film) Film {
+	return Film{
+		Title: ext.DefaultTitle,
+		Genre: impl.ToGenre(ef.Genre),
+	}
+}
+
+func (impl *MapperImpl) ToGenre(genre ext.Genre) Genre {
+	var result Genre
+	switch genre {
+	case ext.GenreDrama:
+		result = GenreDrama
+	case ext.GenreComedy:
+		result = GenreComedy
+	default:
+		result = GenreDrama
+	}
+
+	return result
+}
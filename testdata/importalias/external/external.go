@@ -0,0 +1,14 @@
+package external
+
+const DefaultTitle = "untitled"
+
+type Genre int
+
+const (
+	GenreDrama Genre = iota
+	GenreComedy
+)
+
+type Film struct {
+	Genre Genre
+}
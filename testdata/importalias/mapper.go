@@ -0,0 +1,13 @@
+package importalias
+
+import ext "github.com/liampulles/juryrig/testdata/importalias/external"
+
+//go:generate juryrig gen -o zz.mapper.impl.go
+
+// +juryrig:mapper
+type Mapper interface {
+	// +juryrig:const:ext.DefaultTitle->Title
+	ToFilm(ef ext.Film) Film
+	// +juryrig:enum:_->GenreDrama
+	ToGenre(genre ext.Genre) Genre
+}
@@ -0,0 +1,13 @@
+package importalias
+
+type Genre string
+
+const (
+	GenreDrama  Genre = "drama"
+	GenreComedy Genre = "comedy"
+)
+
+type Film struct {
+	Title string
+	Genre Genre
+}
@@ -0,0 +1,12 @@
+package importaliasbuiltin
+
+import tm "time"
+
+//go:generate juryrig gen -o zz.mapper.impl.go
+
+// +juryrig:mapper
+type Mapper interface {
+	// +juryrig:const:90 * tm.Minute->Length
+	// +juryrig:link:released->Released
+	ToInternalFilm(ef ExternalFilm, released tm.Time) InternalFilm
+}
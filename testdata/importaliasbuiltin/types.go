@@ -0,0 +1,13 @@
+package importaliasbuiltin
+
+import "time"
+
+type ExternalFilm struct {
+	Title string
+}
+
+type InternalFilm struct {
+	Title    string
+	Length   time.Duration
+	Released time.Time
+}
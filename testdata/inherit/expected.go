@@ -0,0 +1,25 @@
+package inherit
+
+import (
+	"strings"
+)
+
+type MapperImpl struct{}
+
+func (impl *MapperImpl) ToInternalUserFilm(ef ExternalFilm, eu ExternalUser) InternalUserFilm {
+	return InternalUserFilm{
+		Title:    ef.Title,
+		Runtime:  ef.Length,
+		Director: strings.ToUpper(ef.Director),
+		User:     eu.Username,
+	}
+}
+
+func (impl *MapperImpl) ToInternalUserFilmV2(user ExternalUser, film ExternalFilmV2) InternalUserFilm {
+	return InternalUserFilm{
+		Title:    film.Name,
+		Runtime:  film.Length,
+		Director: strings.ToUpper(film.Director),
+		User:     user.Username,
+	}
+}
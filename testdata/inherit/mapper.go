@@ -0,0 +1,16 @@
+package inherit
+
+//go:generate juryrig gen -o zz.mapper.impl.go
+
+// +juryrig:mapper
+type Mapper interface {
+	// +juryrig:link:ef.Title->Title
+	// +juryrig:link:ef.Length->Runtime
+	// +juryrig:expr:strings.ToUpper(ef.Director)->Director
+	// +juryrig:link:eu.Username->User
+	ToInternalUserFilm(ef ExternalFilm, eu ExternalUser) InternalUserFilm
+
+	// +juryrig:inherit:ToInternalUserFilm
+	// +juryrig:link:film.Name->Title
+	ToInternalUserFilmV2(user ExternalUser, film ExternalFilmV2) InternalUserFilm
+}
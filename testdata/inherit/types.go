@@ -0,0 +1,24 @@
+package inherit
+
+type ExternalFilm struct {
+	Title    string
+	Length   int
+	Director string
+}
+
+type ExternalFilmV2 struct {
+	Name     string
+	Length   int
+	Director string
+}
+
+type ExternalUser struct {
+	Username string
+}
+
+type InternalUserFilm struct {
+	Title    string
+	Runtime  int
+	Director string
+	User     string
+}
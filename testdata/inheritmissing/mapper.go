@@ -0,0 +1,15 @@
+package inheritmissing
+
+//go:generate juryrig gen -o zz.mapper.impl.go
+
+// +juryrig:mapper
+type Mapper interface {
+	// +juryrig:link:ef.Title->Title
+	// +juryrig:link:ef.Length->Runtime
+	// +juryrig:expr:strings.ToUpper(ef.Director)->Director
+	// +juryrig:link:eu.Username->User
+	ToInternalUserFilm(ef ExternalFilm, eu ExternalUser) InternalUserFilm
+
+	// +juryrig:inherit:ToInternalUserFilm
+	ToInternalUserFilmV2(user ExternalUser, film ExternalFilmV2) InternalUserFilm
+}
@@ -0,0 +1,49 @@
+package inverse
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type MapperImpl struct{}
+
+func (impl *MapperImpl) ToInternalUser(eu ExternalUser) (InternalUser, error) {
+	joined, err := time.Parse(time.RFC1123, eu.Joined)
+	if err != nil {
+		return InternalUser{}, fmt.Errorf("Joined: %w", err)
+	}
+
+	return InternalUser{
+		Name: eu.Username,
+		Contact: Contact{
+			Email: eu.Email,
+		},
+		Joined:   joined,
+		Tier:     impl.ToTier(eu.Level),
+		Nickname: strings.ToLower(eu.Nickname),
+		Source:   "external",
+	}, nil
+}
+
+func (impl *MapperImpl) ToExternalUser(iu InternalUser) ExternalUser {
+	return ExternalUser{
+		Nickname: iu.Nickname,
+		Username: iu.Name,
+		Email:    iu.Contact.Email,
+		Joined:   iu.Joined.Format(time.RFC1123),
+		Level:    impl.FromTier(iu.Tier),
+	}
+}
+
+func (impl *MapperImpl) ToTier(level Level) Tier {
+	return Tier{
+		Rank: level.Value,
+	}
+}
+
+func (impl *MapperImpl) FromTier(tier Tier) Level {
+	return Level{
+		Value: tier.Rank,
+	}
+}
@@ -0,0 +1,24 @@
+package inverse
+
+//go:generate juryrig gen -o zz.mapper.impl.go
+
+// +juryrig:mapper
+type Mapper interface {
+	// +juryrig:link:eu.Username->Name
+	// +juryrig:link:eu.Email->Contact.Email
+	// +juryrig:link:eu.Joined->Joined,format=rfc1123
+	// +juryrig:linkfunc:eu.Level->ToTier->Tier,inverse=FromTier
+	// +juryrig:expr:strings.ToLower(eu.Nickname)->Nickname
+	// +juryrig:const:"external"->Source
+	ToInternalUser(eu ExternalUser) (InternalUser, error)
+
+	// +juryrig:inverse:ToInternalUser
+	// +juryrig:link:iu.Nickname->Nickname
+	ToExternalUser(iu InternalUser) ExternalUser
+
+	// +juryrig:link:level.Value->Rank
+	ToTier(level Level) Tier
+
+	// +juryrig:inverse:ToTier
+	FromTier(tier Tier) Level
+}
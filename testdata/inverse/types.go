@@ -0,0 +1,32 @@
+package inverse
+
+import "time"
+
+type ExternalUser struct {
+	Username string
+	Email    string
+	Joined   string
+	Level    Level
+	Nickname string
+}
+
+type InternalUser struct {
+	Name     string
+	Contact  Contact
+	Joined   time.Time
+	Tier     Tier
+	Nickname string
+	Source   string
+}
+
+type Contact struct {
+	Email string
+}
+
+type Tier struct {
+	Rank int
+}
+
+type Level struct {
+	Value int
+}
@@ -0,0 +1,13 @@
+package inverseexpr
+
+//go:generate juryrig gen -o zz.mapper.impl.go
+
+// +juryrig:mapper
+type Mapper interface {
+	// +juryrig:link:eu.Username->Name
+	// +juryrig:expr:strings.ToLower(eu.Nickname)->Nickname
+	ToInternalUser(eu ExternalUser) InternalUser
+
+	// +juryrig:inverse:ToInternalUser
+	ToExternalUser(iu InternalUser) ExternalUser
+}
@@ -0,0 +1,11 @@
+package inverseexpr
+
+type ExternalUser struct {
+	Username string
+	Nickname string
+}
+
+type InternalUser struct {
+	Name     string
+	Nickname string
+}
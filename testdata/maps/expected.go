@@ -0,0 +1,42 @@
+package maps
+
+type MapperImpl struct{}
+
+func (impl *MapperImpl) ToInternalFilm(ef ExternalFilm) InternalFilm {
+	var sales map[InternalRegion]int
+	if ef.sales != nil {
+		sales = make(map[InternalRegion]int, len(ef.sales))
+		for key, elem := range ef.sales {
+			sales[impl.ToInternalRegion(key)] = elem
+		}
+	}
+
+	return InternalFilm{
+		viewers: impl.ToInternalUsers(ef.viewers),
+		sales:   sales,
+	}
+}
+
+func (impl *MapperImpl) ToInternalUsers(eus map[string]ExternalUser) map[string]InternalUser {
+	var result map[string]InternalUser
+	if eus != nil {
+		result = make(map[string]InternalUser, len(eus))
+		for key, elem := range eus {
+			result[key] = impl.ToInternalUser(elem)
+		}
+	}
+
+	return result
+}
+
+func (impl *MapperImpl) ToInternalUser(eu ExternalUser) InternalUser {
+	return InternalUser{
+		username: eu.username,
+	}
+}
+
+func (impl *MapperImpl) ToInternalRegion(er ExternalRegion) InternalRegion {
+	return InternalRegion{
+		code: er.code,
+	}
+}
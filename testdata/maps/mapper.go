@@ -0,0 +1,11 @@
+package maps
+
+//go:generate juryrig gen -o zz.mapper.impl.go
+
+// +juryrig:mapper
+type Mapper interface {
+	ToInternalFilm(ef ExternalFilm) InternalFilm
+	ToInternalUsers(eus map[string]ExternalUser) map[string]InternalUser
+	ToInternalUser(eu ExternalUser) InternalUser
+	ToInternalRegion(er ExternalRegion) InternalRegion
+}
@@ -0,0 +1,27 @@
+package maps
+
+type ExternalFilm struct {
+	viewers map[string]ExternalUser
+	sales   map[ExternalRegion]int
+}
+
+type ExternalUser struct {
+	username string
+}
+
+type ExternalRegion struct {
+	code string
+}
+
+type InternalFilm struct {
+	viewers map[string]InternalUser
+	sales   map[InternalRegion]int
+}
+
+type InternalUser struct {
+	username string
+}
+
+type InternalRegion struct {
+	code string
+}
@@ -0,0 +1,9 @@
+package matchambiguous
+
+//go:generate juryrig gen -o zz.mapper.impl.go
+
+// +juryrig:mapper
+// +juryrig:match:normalized
+type Mapper interface {
+	ToInternalUser(eu ExternalUser) InternalUser
+}
@@ -0,0 +1,10 @@
+package matchambiguous
+
+type ExternalUser struct {
+	UserName  string
+	user_name string
+}
+
+type InternalUser struct {
+	Username string
+}
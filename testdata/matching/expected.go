@@ -0,0 +1,11 @@
+package matching
+
+type MapperImpl struct{}
+
+func (impl *MapperImpl) ToInternalUser(eu ExternalUser) InternalUser {
+	return InternalUser{
+		UserName:  eu.user_name,
+		Email:     eu.EMAIL,
+		FirstName: eu.first_name,
+	}
+}
@@ -0,0 +1,13 @@
+package matching
+
+type ExternalUser struct {
+	user_name  string
+	EMAIL      string
+	first_name string
+}
+
+type InternalUser struct {
+	UserName  string
+	Email     string
+	FirstName string
+}
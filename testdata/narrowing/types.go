@@ -0,0 +1,9 @@
+package narrowing
+
+type ExternalFilm struct {
+	runtime int64
+}
+
+type InternalFilm struct {
+	runtime int32
+}
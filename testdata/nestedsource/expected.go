@@ -0,0 +1,16 @@
+package nestedsource
+
+type MapperImpl struct{}
+
+func (impl *MapperImpl) ToInternalFilm(ef ExternalFilm) InternalFilm {
+	var director string
+	if ef.meta.director != nil {
+		director = ef.meta.director.name
+	}
+
+	return InternalFilm{
+		director: director,
+		runtime:  ef.meta.runtime,
+		title:    ef.title,
+	}
+}
@@ -0,0 +1,10 @@
+package nestedsource
+
+//go:generate juryrig gen -o zz.mapper.impl.go
+
+// +juryrig:mapper
+type Mapper interface {
+	// +juryrig:link:ef.meta.director.name->director
+	// +juryrig:link:ef.meta.runtime->runtime
+	ToInternalFilm(ef ExternalFilm) InternalFilm
+}
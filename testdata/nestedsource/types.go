@@ -0,0 +1,21 @@
+package nestedsource
+
+type ExternalFilm struct {
+	title string
+	meta  ExternalMeta
+}
+
+type ExternalMeta struct {
+	runtime  int
+	director *ExternalPerson
+}
+
+type ExternalPerson struct {
+	name string
+}
+
+type InternalFilm struct {
+	title    string
+	runtime  int
+	director string
+}
@@ -0,0 +1,22 @@
+package nestedtarget
+
+import (
+	"time"
+)
+
+type MapperImpl struct{}
+
+func (impl *MapperImpl) ToInternalUserFilm(ef ExternalFilm, eu ExternalUser, now time.Time) InternalUserFilm {
+	return InternalUserFilm{
+		user: InternalUser{
+			username: eu.username,
+			account: &InternalAccount{
+				email: eu.email,
+				// verified: (ignored),
+			},
+			age: eu.age,
+		},
+		released: now,
+		title:    ef.title,
+	}
+}
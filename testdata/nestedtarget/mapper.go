@@ -0,0 +1,15 @@
+package nestedtarget
+
+import "time"
+
+//go:generate juryrig gen -o zz.mapper.impl.go
+
+// +juryrig:mapper
+type Mapper interface {
+	// +juryrig:link:eu.username->user.username
+	// +juryrig:link:now->released
+	// +juryrig:link:eu.email->user.account.email
+	// +juryrig:ignore:user.account.verified
+	// +juryrig:link:eu.age->user.age
+	ToInternalUserFilm(ef ExternalFilm, eu ExternalUser, now time.Time) InternalUserFilm
+}
@@ -0,0 +1,30 @@
+package nestedtarget
+
+import "time"
+
+type ExternalFilm struct {
+	title string
+}
+
+type ExternalUser struct {
+	username string
+	email    string
+	age      int
+}
+
+type InternalUser struct {
+	username string
+	age      int
+	account  *InternalAccount
+}
+
+type InternalAccount struct {
+	email    string
+	verified bool
+}
+
+type InternalUserFilm struct {
+	title    string
+	released time.Time
+	user     InternalUser
+}
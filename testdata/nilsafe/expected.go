@@ -0,0 +1,36 @@
+package nilsafe
+
+type MapperImpl struct{}
+
+func (impl *MapperImpl) ToInternalFilm(ef *ExternalFilm) InternalFilm {
+	var runtime int
+	if ef != nil && ef.details != nil {
+		runtime = ef.details.runtime
+	}
+
+	var length Length
+	if ef != nil && ef.details != nil {
+		length = impl.ToLength(ef.details.runtime)
+	}
+
+	var title string
+	if ef != nil {
+		title = ef.title
+	}
+
+	return InternalFilm{
+		runtime: runtime,
+		studio:  ef.details.studio.name,
+		length:  length,
+		rating:  impl.ToRating(ef.details.rating),
+		title:   title,
+	}
+}
+
+func (impl *MapperImpl) ToLength(runtime int) Length {
+	return Length(runtime)
+}
+
+func (impl *MapperImpl) ToRating(rating int) Rating {
+	return Rating(rating)
+}
@@ -0,0 +1,14 @@
+package nilsafe
+
+//go:generate juryrig gen -o zz.mapper.impl.go
+
+// +juryrig:mapper
+type Mapper interface {
+	// +juryrig:link:ef.details.runtime->runtime
+	// +juryrig:link:ef.details.studio.name->studio,nonnil
+	// +juryrig:linkfunc:ef.details.runtime->ToLength->length
+	// +juryrig:linkfunc:ef.details.rating->ToRating->rating,nonnil
+	ToInternalFilm(ef *ExternalFilm) InternalFilm
+	ToLength(runtime int) Length
+	ToRating(rating int) Rating
+}
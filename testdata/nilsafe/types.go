@@ -0,0 +1,28 @@
+package nilsafe
+
+type ExternalFilm struct {
+	title   string
+	details *ExternalDetails
+}
+
+type ExternalDetails struct {
+	runtime int
+	rating  int
+	studio  *ExternalStudio
+}
+
+type ExternalStudio struct {
+	name string
+}
+
+type InternalFilm struct {
+	title   string
+	runtime int
+	studio  string
+	length  Length
+	rating  Rating
+}
+
+type Length int
+
+type Rating int
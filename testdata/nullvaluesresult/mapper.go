@@ -0,0 +1,9 @@
+package nullvaluesresult
+
+//go:generate juryrig gen -o zz.mapper.impl.go
+
+// +juryrig:mapper
+type Mapper interface {
+	// +juryrig:nullvalues:skip
+	ToFilm(fp FilmPatch) Film
+}
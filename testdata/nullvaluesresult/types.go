@@ -0,0 +1,9 @@
+package nullvaluesresult
+
+type FilmPatch struct {
+	Title *string
+}
+
+type Film struct {
+	Title string
+}
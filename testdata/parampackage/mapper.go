@@ -0,0 +1,9 @@
+package parampackage
+
+//go:generate juryrig gen -o zz.mapper.impl.go
+
+// +juryrig:mapper
+type Mapper interface {
+	// +juryrig:link:time->At
+	ToEvent(time string) (Event, error)
+}
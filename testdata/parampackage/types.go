@@ -0,0 +1,7 @@
+package parampackage
+
+import "time"
+
+type Event struct {
+	At time.Time
+}
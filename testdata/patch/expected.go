@@ -0,0 +1,117 @@
+package patch
+
+import (
+	"fmt"
+	"time"
+)
+
+type MapperImpl struct{}
+
+func (impl *MapperImpl) PatchFilm(fp FilmPatch, film *Film) error {
+	var released time.Time
+	if fp.Released != nil {
+		released2, err := time.Parse(time.RFC3339, *fp.Released)
+		if err != nil {
+			return fmt.Errorf("Released: %w", err)
+		}
+		released = released2
+	}
+
+	if fp.Details != nil && fp.Details.Director != "" {
+		film.Director = fp.Details.Director
+	}
+	if fp.Details != nil {
+		film.Runtime = impl.ToRuntime(fp.Details.Runtime)
+	}
+	if fp.Studio != nil {
+		if film.Info == nil {
+			film.Info = &Info{}
+		}
+		film.Info.Studio = *fp.Studio
+	}
+	if fp.Year != nil {
+		if film.Info == nil {
+			film.Info = &Info{}
+		}
+		film.Info.Year = *fp.Year
+	}
+	// film.ID: (ignored)
+	if fp.Title != nil {
+		film.Title = *fp.Title
+	}
+	if fp.Length != nil {
+		film.Length = *fp.Length
+	}
+	if fp.Released != nil {
+		film.Released = released
+	}
+	if fp.Tags != nil {
+		film.Tags = fp.Tags
+	}
+	if fp.Rating != 0 {
+		film.Rating = fp.Rating
+	}
+
+	return nil
+}
+
+func (impl *MapperImpl) ReplaceFilm(fp FilmPatch, film *Film) error {
+	var director string
+	if fp.Details != nil {
+		director = fp.Details.Director
+	}
+
+	var runtime Runtime
+	if fp.Details != nil {
+		runtime = impl.ToRuntime(fp.Details.Runtime)
+	}
+
+	var infoStudio string
+	if fp.Studio != nil {
+		infoStudio = *fp.Studio
+	}
+
+	var infoYear int
+	if fp.Year != nil {
+		infoYear = *fp.Year
+	}
+
+	var title string
+	if fp.Title != nil {
+		title = *fp.Title
+	}
+
+	var length int
+	if fp.Length != nil {
+		length = *fp.Length
+	}
+
+	var released time.Time
+	if fp.Released != nil {
+		released2, err := time.Parse(time.RFC3339, *fp.Released)
+		if err != nil {
+			return fmt.Errorf("Released: %w", err)
+		}
+		released = released2
+	}
+
+	film.Director = director
+	film.Runtime = runtime
+	if film.Info == nil {
+		film.Info = &Info{}
+	}
+	film.Info.Studio = infoStudio
+	film.Info.Year = infoYear
+	// film.ID: (ignored)
+	film.Title = title
+	film.Length = length
+	film.Released = released
+	film.Tags = fp.Tags
+	film.Rating = fp.Rating
+
+	return nil
+}
+
+func (impl *MapperImpl) ToRuntime(minutes int) Runtime {
+	return Runtime(minutes)
+}
@@ -0,0 +1,24 @@
+package patch
+
+//go:generate juryrig gen -o zz.mapper.impl.go
+
+// +juryrig:mapper
+// +juryrig:nullvalues:skip
+type Mapper interface {
+	// +juryrig:link:fp.Details.Director->Director
+	// +juryrig:linkfunc:fp.Details.Runtime->ToRuntime->Runtime
+	// +juryrig:link:fp.Studio->Info.Studio
+	// +juryrig:link:fp.Year->Info.Year
+	// +juryrig:ignore:ID
+	PatchFilm(fp FilmPatch, film *Film) error
+
+	// +juryrig:nullvalues:set
+	// +juryrig:link:fp.Details.Director->Director
+	// +juryrig:linkfunc:fp.Details.Runtime->ToRuntime->Runtime
+	// +juryrig:link:fp.Studio->Info.Studio
+	// +juryrig:link:fp.Year->Info.Year
+	// +juryrig:ignore:ID
+	ReplaceFilm(fp FilmPatch, film *Film) error
+
+	ToRuntime(minutes int) Runtime
+}
@@ -0,0 +1,38 @@
+package patch
+
+import "time"
+
+type FilmPatch struct {
+	Title    *string
+	Length   *int
+	Released *string
+	Tags     []string
+	Rating   float64
+	Details  *Details
+	Studio   *string
+	Year     *int
+}
+
+type Details struct {
+	Director string
+	Runtime  int
+}
+
+type Film struct {
+	ID       int
+	Title    string
+	Length   int
+	Released time.Time
+	Tags     []string
+	Rating   float64
+	Director string
+	Runtime  Runtime
+	Info     *Info
+}
+
+type Runtime int
+
+type Info struct {
+	Studio string
+	Year   int
+}
@@ -0,0 +1,30 @@
+package pointerresult
+
+type MapperImpl struct{}
+
+func (impl *MapperImpl) ToInternalFilm(ef ExternalFilm) *InternalFilm {
+	return &InternalFilm{
+		title: ef.title,
+	}
+}
+
+func (impl *MapperImpl) ToInternalUserFilm(ef *ExternalFilm, eu *ExternalUser) *InternalUserFilm {
+	if ef == nil && eu == nil {
+		return nil
+	}
+
+	var title string
+	if ef != nil {
+		title = ef.title
+	}
+
+	var username string
+	if eu != nil {
+		username = eu.username
+	}
+
+	return &InternalUserFilm{
+		title:    title,
+		username: username,
+	}
+}
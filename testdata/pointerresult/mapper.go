@@ -0,0 +1,12 @@
+package pointerresult
+
+//go:generate juryrig gen -o zz.mapper.impl.go
+
+// +juryrig:mapper
+type Mapper interface {
+	ToInternalFilm(ef ExternalFilm) *InternalFilm
+	// +juryrig:returnnil
+	// +juryrig:link:ef.title->title
+	// +juryrig:link:eu.username->username
+	ToInternalUserFilm(ef *ExternalFilm, eu *ExternalUser) *InternalUserFilm
+}
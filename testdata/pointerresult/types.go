@@ -0,0 +1,18 @@
+package pointerresult
+
+type ExternalFilm struct {
+	title string
+}
+
+type ExternalUser struct {
+	username string
+}
+
+type InternalFilm struct {
+	title string
+}
+
+type InternalUserFilm struct {
+	title    string
+	username string
+}
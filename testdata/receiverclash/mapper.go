@@ -0,0 +1,9 @@
+package receiverclash
+
+//go:generate juryrig gen -o zz.mapper.impl.go
+
+// +juryrig:mapper
+// +juryrig:receiver:m
+type Mapper interface {
+	ToInternalFilm(m ExternalFilm) InternalFilm
+}
@@ -0,0 +1,9 @@
+package receiverclash
+
+type ExternalFilm struct {
+	Title string
+}
+
+type InternalFilm struct {
+	Title string
+}
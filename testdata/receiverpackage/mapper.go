@@ -0,0 +1,9 @@
+package receiverpackage
+
+//go:generate juryrig gen -o zz.mapper.impl.go
+
+// +juryrig:mapper
+// +juryrig:receiver:time
+type Mapper interface {
+	ToInternalFilm(ef ExternalFilm) InternalFilm
+}
@@ -0,0 +1,17 @@
+package shadowtype
+
+type MapperImpl struct{}
+
+func (impl *MapperImpl) ToInternalFilm(ef ExternalFilm) InternalFilm {
+	var info2 info
+	if ef.Meta != nil {
+		info2 = ef.Meta.Info
+	}
+
+	return InternalFilm{
+		Info: info2,
+		Other: info{
+			Name: ef.Name,
+		},
+	}
+}
@@ -0,0 +1,10 @@
+package shadowtype
+
+//go:generate juryrig gen -o zz.mapper.impl.go
+
+// +juryrig:mapper
+type Mapper interface {
+	// +juryrig:link:ef.Meta.Info->Info
+	// +juryrig:link:ef.Name->Other.Name
+	ToInternalFilm(ef ExternalFilm) InternalFilm
+}
@@ -0,0 +1,19 @@
+package shadowtype
+
+type ExternalFilm struct {
+	Name string
+	Meta *Meta
+}
+
+type Meta struct {
+	Info info
+}
+
+type InternalFilm struct {
+	Info  info
+	Other info
+}
+
+type info struct {
+	Name string
+}
@@ -0,0 +1,51 @@
+package slices
+
+import (
+	"fmt"
+)
+
+type MapperImpl struct{}
+
+func (impl *MapperImpl) ToInternalFilm(ef ExternalFilm) (InternalFilm, error) {
+	var ratings []InternalRating
+	if ef.ratings != nil {
+		ratings = make([]InternalRating, 0, len(ef.ratings))
+		for i, elem := range ef.ratings {
+			ratingsElem, err := impl.ToInternalRating(elem)
+			if err != nil {
+				return InternalFilm{}, fmt.Errorf("ratings[%d]: %w", i, err)
+			}
+			ratings = append(ratings, ratingsElem)
+		}
+	}
+
+	return InternalFilm{
+		viewers: impl.ToInternalUsers(ef.audience),
+		tags:    ef.tags,
+		ratings: ratings,
+	}, nil
+}
+
+func (impl *MapperImpl) ToInternalUsers(eus []ExternalUser) []InternalUser {
+	var result []InternalUser
+	if eus != nil {
+		result = make([]InternalUser, 0, len(eus))
+		for _, elem := range eus {
+			result = append(result, impl.ToInternalUser(elem))
+		}
+	}
+
+	return result
+}
+
+func (impl *MapperImpl) ToInternalUser(eu ExternalUser) InternalUser {
+	return InternalUser{
+		username: eu.username,
+	}
+}
+
+func (impl *MapperImpl) ToInternalRating(er ExternalRating) (InternalRating, error) {
+	return InternalRating{
+		score: er.score,
+	}, nil
+}
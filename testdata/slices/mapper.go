@@ -0,0 +1,12 @@
+package slices
+
+//go:generate juryrig gen -o zz.mapper.impl.go
+
+// +juryrig:mapper
+type Mapper interface {
+	// +juryrig:link:ef.audience->viewers
+	ToInternalFilm(ef ExternalFilm) (InternalFilm, error)
+	ToInternalUsers(eus []ExternalUser) []InternalUser
+	ToInternalUser(eu ExternalUser) InternalUser
+	ToInternalRating(er ExternalRating) (InternalRating, error)
+}
@@ -0,0 +1,29 @@
+package slices
+
+type ExternalFilm struct {
+	tags     []string
+	audience []ExternalUser
+	ratings  []ExternalRating
+}
+
+type ExternalUser struct {
+	username string
+}
+
+type ExternalRating struct {
+	score int
+}
+
+type InternalFilm struct {
+	tags    []string
+	viewers []InternalUser
+	ratings []InternalRating
+}
+
+type InternalUser struct {
+	username string
+}
+
+type InternalRating struct {
+	score int
+}
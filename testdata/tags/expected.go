@@ -0,0 +1,13 @@
+package tags
+
+type MapperImpl struct{}
+
+func (impl *MapperImpl) ToFilmRow(dto FilmDTO) FilmRow {
+	return FilmRow{
+		Name:     dto.Title,
+		Runtime:  dto.Length,
+		Director: dto.Director,
+		// Secret: (ignored),
+		Notes: dto.Notes,
+	}
+}
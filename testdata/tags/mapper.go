@@ -0,0 +1,9 @@
+package tags
+
+//go:generate juryrig gen -o zz.mapper.impl.go
+
+// +juryrig:mapper
+// +juryrig:tag:json->db
+type Mapper interface {
+	ToFilmRow(dto FilmDTO) FilmRow
+}
@@ -0,0 +1,17 @@
+package tags
+
+type FilmDTO struct {
+	Title    string `json:"title"`
+	Length   int    `json:"runtime,omitempty"`
+	Director string `json:"director_name"`
+	Secret   string `json:"-"`
+	Notes    string
+}
+
+type FilmRow struct {
+	Name     string `db:"title"`
+	Runtime  int    `db:"runtime"`
+	Director string `juryrig:"director_name"`
+	Secret   string `juryrig:"-"`
+	Notes    string
+}
@@ -0,0 +1,25 @@
+package typeconv
+
+type MapperImpl struct{}
+
+func (impl *MapperImpl) ToInternalFilm(ef ExternalFilm) InternalFilm {
+	return InternalFilm{
+		rating:  int8(ef.rating),
+		title:   Title(ef.title),
+		runtime: int64(ef.runtime),
+		views:   int64(ef.views),
+		score:   float64(ef.score),
+	}
+}
+
+func (impl *MapperImpl) ToTitles(titles []string) []Title {
+	var result []Title
+	if titles != nil {
+		result = make([]Title, 0, len(titles))
+		for _, elem := range titles {
+			result = append(result, Title(elem))
+		}
+	}
+
+	return result
+}
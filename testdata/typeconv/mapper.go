@@ -0,0 +1,10 @@
+package typeconv
+
+//go:generate juryrig gen -o zz.mapper.impl.go
+
+// +juryrig:mapper
+type Mapper interface {
+	// +juryrig:link:ef.rating->rating,narrow
+	ToInternalFilm(ef ExternalFilm) InternalFilm
+	ToTitles(titles []string) []Title
+}
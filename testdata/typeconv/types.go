@@ -0,0 +1,19 @@
+package typeconv
+
+type Title string
+
+type ExternalFilm struct {
+	title   string
+	runtime int32
+	views   uint32
+	score   float32
+	rating  float64
+}
+
+type InternalFilm struct {
+	title   Title
+	runtime int64
+	views   int64
+	score   float64
+	rating  int8
+}
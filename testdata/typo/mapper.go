@@ -0,0 +1,9 @@
+package typo
+
+//go:generate juryrig gen -o zz.mapper.impl.go
+
+// +juryrig:mapper
+type Mapper interface {
+	// +juryrig:link:ef.titel->title
+	ToInternalFilm(ef ExternalFilm) InternalFilm
+}
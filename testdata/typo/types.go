@@ -0,0 +1,9 @@
+package typo
+
+type ExternalFilm struct {
+	title string
+}
+
+type InternalFilm struct {
+	title string
+}
@@ -0,0 +1,8 @@
+package unmapped
+
+//go:generate juryrig gen -o zz.mapper.impl.go
+
+// +juryrig:mapper
+type Mapper interface {
+	ToInternalFilm(ef ExternalFilm) InternalFilm
+}
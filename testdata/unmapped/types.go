@@ -0,0 +1,10 @@
+package unmapped
+
+type ExternalFilm struct {
+	title string
+}
+
+type InternalFilm struct {
+	title    string
+	director string
+}
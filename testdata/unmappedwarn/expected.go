@@ -0,0 +1,9 @@
+package unmappedwarn
+
+type MapperImpl struct{}
+
+func (impl *MapperImpl) ToInternalFilm(ef ExternalFilm) InternalFilm {
+	return InternalFilm{
+		title: ef.title,
+	}
+}
@@ -0,0 +1,9 @@
+package unmappedwarn
+
+//go:generate juryrig gen -o zz.mapper.impl.go
+
+// +juryrig:mapper
+// +juryrig:unmapped:warn
+type Mapper interface {
+	ToInternalFilm(ef ExternalFilm) InternalFilm
+}
@@ -0,0 +1,10 @@
+package unusedembedded
+
+//go:generate juryrig gen -o zz.mapper.impl.go
+
+// +juryrig:mapper
+// +juryrig:unmappedsource:error
+type Mapper interface {
+	// +juryrig:link:eu.Email->Contact
+	ToInternalUser(eu ExternalUser) InternalUser
+}
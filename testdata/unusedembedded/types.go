@@ -0,0 +1,22 @@
+package unusedembedded
+
+type ExternalUser struct {
+	*Base
+	*Audit
+	Age int
+}
+
+type Base struct {
+	Name  string
+	Email string
+}
+
+type Audit struct {
+	CreatedBy string
+}
+
+type InternalUser struct {
+	Name    string
+	Age     int
+	Contact string
+}
@@ -0,0 +1,9 @@
+package unusedsource
+
+//go:generate juryrig gen -o zz.mapper.impl.go
+
+// +juryrig:mapper
+// +juryrig:unmappedsource:error
+type Mapper interface {
+	ToInternalUser(eu ExternalUser) InternalUser
+}
@@ -0,0 +1,10 @@
+package unusedsource
+
+type ExternalUser struct {
+	username string
+	age      int
+}
+
+type InternalUser struct {
+	username string
+}
@@ -0,0 +1,50 @@
+package update
+
+import (
+	"fmt"
+	"strconv"
+)
+
+type MapperImpl struct{}
+
+func (impl *MapperImpl) UpdateFilm(ef ExternalFilm, target *InternalFilm) {
+	target.Name = ef.Title
+	target.Runtime = impl.ToRuntime(ef.Length)
+	if target.Director == nil {
+		target.Director = &Person{}
+	}
+	target.Director.Name = ef.Director
+	// target.ID: (ignored)
+	// target.Released: (ignored)
+}
+
+func (impl *MapperImpl) UpdateReleasedFilm(ef ExternalFilm, target *InternalFilm) error {
+	released, err := impl.ParseYear(ef.Year)
+	if err != nil {
+		return fmt.Errorf("Released: %w", err)
+	}
+
+	target.Name = ef.Title
+	target.Runtime = impl.ToRuntime(ef.Length)
+	if target.Director == nil {
+		target.Director = &Person{}
+	}
+	target.Director.Name = ef.Director
+	target.Released = released
+	// target.ID: (ignored)
+
+	return nil
+}
+
+func (impl *MapperImpl) ToRuntime(length int) Runtime {
+	return Runtime(length)
+}
+
+func (impl *MapperImpl) ParseYear(year string) (int, error) {
+	result, err := strconv.Atoi(year)
+	if err != nil {
+		return 0, err
+	}
+
+	return result, nil
+}
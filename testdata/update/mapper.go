@@ -0,0 +1,23 @@
+package update
+
+//go:generate juryrig gen -o zz.mapper.impl.go
+
+// +juryrig:mapper
+type Mapper interface {
+	// +juryrig:link:ef.Title->Name
+	// +juryrig:linkfunc:ef.Length->ToRuntime->Runtime
+	// +juryrig:link:ef.Director->Director.Name
+	// +juryrig:ignore:ID
+	// +juryrig:ignore:Released
+	UpdateFilm(ef ExternalFilm, target *InternalFilm)
+
+	// +juryrig:link:ef.Title->Name
+	// +juryrig:linkfunc:ef.Length->ToRuntime->Runtime
+	// +juryrig:link:ef.Director->Director.Name
+	// +juryrig:linkfunc:ef.Year->ParseYear->Released
+	// +juryrig:ignore:ID
+	UpdateReleasedFilm(ef ExternalFilm, target *InternalFilm) error
+
+	ToRuntime(length int) Runtime
+	ParseYear(year string) (int, error)
+}
@@ -0,0 +1,22 @@
+package update
+
+type ExternalFilm struct {
+	Title    string
+	Length   int
+	Director string
+	Year     string
+}
+
+type InternalFilm struct {
+	ID       int
+	Name     string
+	Runtime  Runtime
+	Director *Person
+	Released int
+}
+
+type Runtime int
+
+type Person struct {
+	Name string
+}